@@ -2,11 +2,14 @@ package main
 
 import (
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"log"
 	"midi_manipulator/pkg/backlight"
 	"midi_manipulator/pkg/config"
 	midiHermophrodite "midi_manipulator/pkg/midi"
 	"midi_manipulator/pkg/model"
+	"midi_manipulator/pkg/state"
+	"os"
 
 	"git.miem.hse.ru/hubman/hubman-lib"
 	"git.miem.hse.ru/hubman/hubman-lib/core"
@@ -15,6 +18,19 @@ import (
 	_ "gitlab.com/gomidi/midi/v2/drivers/rtmididrv"
 )
 
+// Representation of fatal log hook saving control state before termination of process
+type stateFlushHook struct {
+	store *state.FileStore
+}
+
+// Function writes control state and terminates process after fatal log entry
+func (h *stateFlushHook) OnWrite(_ *zapcore.CheckedEntry, _ []zapcore.Field) {
+	if h.store != nil {
+		h.store.Flush()
+	}
+	os.Exit(1)
+}
+
 // Application entry point
 func main() {
 	defer midi.CloseDriver()
@@ -39,11 +55,11 @@ func setupApp(systemConfig *core.SystemConfig, userConfig *config.UserConfig) {
 	}
 
 	app := core.NewContainer(agentConf.System.Logging)
-	logger := app.Logger()
+	flushHook := &stateFlushHook{}
+	logger := app.Logger().WithOptions(zap.WithFatalHook(flushHook))
 	checkManager := core.NewCheckManager()
 
 	deviceManager := midiHermophrodite.NewDeviceManager(logger, checkManager)
-	defer deviceManager.Close()
 
 	backlightConfig, err := backlight.InitConfig("configs/backlight_config.yaml")
 	if err != nil {
@@ -51,18 +67,47 @@ func setupApp(systemConfig *core.SystemConfig, userConfig *config.UserConfig) {
 	}
 
 	deviceManager.SetBacklightConfig(backlightConfig)
+
+	stateFile := userConfig.StateFile
+	if stateFile == "" {
+		stateFile = config.DefaultStateFile
+	}
+	stateStore, err := state.NewFileStore(stateFile, logger)
+	if err != nil {
+		logger.Fatal("can't init control state store", zap.Error(err))
+	}
+	flushHook.store = stateStore
+
+	deviceManager.SetStateStore(stateStore)
 	signals := deviceManager.GetSignals()
 
 	app.RegisterPlugin(
 		hubman.NewAgentPlugin(
-			app.Logger(),
+			logger,
 			agentConf,
 			hubman.WithManipulator(
 				hubman.WithSignal[model.NotePushed](),
 				hubman.WithSignal[model.NoteHold](),
+				hubman.WithSignal[model.NoteRepeat](),
 				hubman.WithSignal[model.NoteReleased](),
 				hubman.WithSignal[model.NoteReleasedAfterHold](),
+				hubman.WithSignal[model.NoteToggled](),
+				hubman.WithSignal[model.GroupSelectionChanged](),
+				hubman.WithSignal[model.NoteDoubleTapped](),
+				hubman.WithSignal[model.NoteMultiTapped](),
+				hubman.WithSignal[model.ChordPressed](),
+				hubman.WithSignal[model.ChordReleased](),
 				hubman.WithSignal[model.ControlPushed](),
+				hubman.WithSignal[model.HighResolutionControlPushed](),
+				hubman.WithSignal[model.ControlValueReported](),
+				hubman.WithSignal[model.PitchBendChanged](),
+				hubman.WithSignal[model.ChannelPressureChanged](),
+				hubman.WithSignal[model.PolyAftertouchChanged](),
+				hubman.WithSignal[model.ProgramChanged](),
+				hubman.WithSignal[model.SysExReceived](),
+				hubman.WithSignal[model.TransportStarted](),
+				hubman.WithSignal[model.TransportStopped](),
+				hubman.WithSignal[model.TempoChanged](),
 				hubman.WithSignal[model.NamespaceChanged](),
 				hubman.WithChannel(signals),
 			),
@@ -113,6 +158,16 @@ func setupApp(systemConfig *core.SystemConfig, userConfig *config.UserConfig) {
 					parser(&cmd)
 					return deviceManager.ExecuteOnDevice(cmd.DeviceAlias, cmd)
 				}),
+				hubman.WithCommand(model.SetControlValueCommand{}, func(s core.SerializedCommand, parser executor.CommandParser) error {
+					var cmd model.SetControlValueCommand
+					parser(&cmd)
+					return deviceManager.ExecuteOnDevice(cmd.DeviceAlias, cmd)
+				}),
+				hubman.WithCommand(model.GetControlValueCommand{}, func(s core.SerializedCommand, parser executor.CommandParser) error {
+					var cmd model.GetControlValueCommand
+					parser(&cmd)
+					return deviceManager.ExecuteOnDevice(cmd.DeviceAlias, cmd)
+				}),
 			),
 			hubman.WithOnConfigRefresh(func(configuration core.AgentConfiguration) {
 				update, _ := configuration.User.(*config.UserConfig)
@@ -125,4 +180,10 @@ func setupApp(systemConfig *core.SystemConfig, userConfig *config.UserConfig) {
 	deviceManager.UpdateDevices(userConfig.MidiDevices)
 
 	<-app.WaitShutdown()
+
+	// DEVICES ARE STOPPED BEFORE LAST WRITE OF CONTROL STATE
+	deviceManager.Close()
+	if err := stateStore.Flush(); err != nil {
+		logger.Warn("Unable to save control state", zap.String("path", stateFile), zap.Error(err))
+	}
 }
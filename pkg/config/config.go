@@ -1,43 +1,174 @@
 package config
 
 import (
+	"encoding/hex"
 	"fmt"
 	"gopkg.in/yaml.v3"
+	"strings"
 )
 
 // Default reconnect interval time
 const MinReconnectIntervalMs = 1000
 
+// Default path of file with persisted values of controls
+const DefaultStateFile = "configs/control_state.json"
+
+// Name of hold level used for keys without configured hold levels
+const DefaultHoldLevel = "hold"
+
+// Types of high resolution controls
+const (
+	HighResolutionCC14 = "cc14"
+	HighResolutionNRPN = "nrpn"
+	HighResolutionRPN  = "rpn"
+)
+
+// Types of velocity curves
+const (
+	VelocityLinear      = "linear"
+	VelocityLogarithmic = "logarithmic"
+	VelocityExponential = "exponential"
+	VelocityFixed       = "fixed"
+	VelocityCustom      = "custom"
+)
+
+// Encoder modes of accumulate controls
+const (
+	EncoderTriggers       = "triggers"
+	EncoderTwosComplement = "twos_complement"
+	EncoderSignedBit      = "signed_bit"
+	EncoderBinaryOffset   = "binary_offset"
+	EncoderAbsolute       = "absolute"
+)
+
 // Representation of configurtaion for trigger values used by set of controls
 type TriggerValues struct {
 	Increment int `json:"increment" yaml:"increment"`
 	Decrement int `json:"decrement" yaml:"decrement"`
 }
 
+// Representation of configurtaion for single point of encoder acceleration curve
+type AccelerationPoint struct {
+	Interval   int `json:"interval" yaml:"interval"`
+	Multiplier int `json:"multiplier" yaml:"multiplier"`
+}
+
+// Representation of configurtaion for backlight feedback of accumulated value
+type ControlFeedback struct {
+	Keys   []int    `json:"keys" yaml:"keys"`
+	Colors []string `json:"colors" yaml:"colors"`
+}
+
 // Representation of configurtaion for single set of controls
 type Controls struct {
-	Keys         []int         `json:"keys" yaml:"keys"`
-	Rotate       bool          `json:"rotate" yaml:"rotate"`
-	ValueRange   [2]int        `json:"value_range" yaml:"value_range"`
-	InitialValue int           `json:"initial_value" yaml:"initial_value"`
-	Triggers     TriggerValues `json:"triggers" yaml:"triggers"`
+	Keys         []int               `json:"keys" yaml:"keys"`
+	Rotate       bool                `json:"rotate" yaml:"rotate"`
+	Passthrough  bool                `json:"passthrough" yaml:"passthrough"`
+	ValueRange   [2]int              `json:"value_range" yaml:"value_range"`
+	InitialValue int                 `json:"initial_value" yaml:"initial_value"`
+	Triggers     TriggerValues       `json:"triggers" yaml:"triggers"`
+	EncoderMode  string              `json:"encoder_mode" yaml:"encoder_mode"`
+	Pickup       bool                `json:"pickup" yaml:"pickup"`
+	Step         int                 `json:"step" yaml:"step"`
+	Acceleration []AccelerationPoint `json:"acceleration" yaml:"acceleration"`
+	Feedback     ControlFeedback     `json:"feedback" yaml:"feedback"`
+}
+
+// Representation of configurtaion for keys switching on/off state on each press
+type ToggleKeys struct {
+	Keys     []int  `json:"keys" yaml:"keys"`
+	OnColor  string `json:"on_color" yaml:"on_color"`
+	OffColor string `json:"off_color" yaml:"off_color"`
+}
+
+// Representation of configurtaion for named group of keys with exclusive selection
+type KeyGroup struct {
+	Name     string `json:"name" yaml:"name"`
+	Keys     []int  `json:"keys" yaml:"keys"`
+	OnColor  string `json:"on_color" yaml:"on_color"`
+	OffColor string `json:"off_color" yaml:"off_color"`
+}
+
+// Representation of configurtaion for named combination of keys held together
+type Chord struct {
+	Name   string `json:"name" yaml:"name"`
+	Keys   []int  `json:"keys" yaml:"keys"`
+	Window int    `json:"window" yaml:"window"`
+}
+
+// Representation of configurtaion for named pattern of incoming SysEx message
+type SysExSignal struct {
+	Name    string `json:"name" yaml:"name"`
+	Pattern string `json:"pattern" yaml:"pattern"`
+}
+
+// Representation of configurtaion for control sending 14-bit values
+type HighResolutionControl struct {
+	Type      string `json:"type" yaml:"type"`
+	MSB       int    `json:"msb" yaml:"msb"`
+	LSB       int    `json:"lsb" yaml:"lsb"`
+	Parameter int    `json:"parameter" yaml:"parameter"`
+}
+
+// Representation of configurtaion for velocity response curve of range of keys
+type VelocityCurve struct {
+	KeyRange []int  `json:"key_range" yaml:"key_range"`
+	Type     string `json:"type" yaml:"type"`
+	Value    int    `json:"value" yaml:"value"`
+	Table    []int  `json:"table" yaml:"table"`
+}
+
+// Representation of configurtaion for filtering noisy values of controls
+type ControlFilter struct {
+	Keys          []int `json:"keys" yaml:"keys"`
+	Deadband      int   `json:"deadband" yaml:"deadband"`
+	Hysteresis    int   `json:"hysteresis" yaml:"hysteresis"`
+	MovingAverage int   `json:"moving_average" yaml:"moving_average"`
+	MaxRate       int   `json:"max_rate" yaml:"max_rate"`
+}
+
+// Representation of configurtaion for named hold level of range of keys
+type HoldLevel struct {
+	Name     string `json:"name" yaml:"name"`
+	Delta    int    `json:"delta" yaml:"delta"`
+	KeyRange []int  `json:"key_range" yaml:"key_range"`
+}
+
+// Representation of configurtaion for auto-repeat of held keys
+type KeyRepeat struct {
+	KeyRange []int `json:"key_range" yaml:"key_range"`
+	Interval int   `json:"interval" yaml:"interval"`
 }
 
 // Representation of single device configurtaion
 type DeviceConfig struct {
-	DeviceName        string     `json:"device_name" yaml:"device_name"`
-	StartupDelay      int        `json:"startup_delay" yaml:"startup_delay"`
-	ReconnectInterval int        `json:"reconnect_interval" yaml:"reconnect_interval"`
-	Active            bool       `json:"active" yaml:"active"`
-	HoldDelta         int        `json:"hold_delta" yaml:"hold_delta"`
-	Namespace         string     `json:"namespace" yaml:"namespace"`
-	Controls          []Controls `json:"accumulate_controls" yaml:"accumulate_controls"`
-	BlinkingPeriodMS  int        `json:"blinking_period_ms" yaml:"blinking_period_ms"`
+	DeviceName        string                  `json:"device_name" yaml:"device_name"`
+	StartupDelay      int                     `json:"startup_delay" yaml:"startup_delay"`
+	ReconnectInterval int                     `json:"reconnect_interval" yaml:"reconnect_interval"`
+	Active            bool                    `json:"active" yaml:"active"`
+	HoldDelta         int                     `json:"hold_delta" yaml:"hold_delta"`
+	HoldLevels        []HoldLevel             `json:"hold_levels" yaml:"hold_levels"`
+	Repeat            []KeyRepeat             `json:"repeat" yaml:"repeat"`
+	Namespace         string                  `json:"namespace" yaml:"namespace"`
+	Controls          []Controls              `json:"accumulate_controls" yaml:"accumulate_controls"`
+	ControlFilters    []ControlFilter         `json:"control_filters" yaml:"control_filters"`
+	ButtonControls    []int                   `json:"button_controls" yaml:"button_controls"`
+	BlinkingPeriodMS  int                     `json:"blinking_period_ms" yaml:"blinking_period_ms"`
+	TapWindow         int                     `json:"tap_window" yaml:"tap_window"`
+	ToggleKeys        []ToggleKeys            `json:"toggle_keys" yaml:"toggle_keys"`
+	KeyGroups         []KeyGroup              `json:"key_groups" yaml:"key_groups"`
+	Chords            []Chord                 `json:"chords" yaml:"chords"`
+	SysExSignals      []SysExSignal           `json:"sysex_signals" yaml:"sysex_signals"`
+	HighResolution    []HighResolutionControl `json:"high_resolution_controls" yaml:"high_resolution_controls"`
+	VelocityCurves    []VelocityCurve         `json:"velocity_curves" yaml:"velocity_curves"`
+	KeyNames          map[int]string          `json:"key_names" yaml:"key_names"`
+	ControlNames      map[int]string          `json:"control_names" yaml:"control_names"`
 }
 
 // Representation of user configurtaion
 type UserConfig struct {
 	MidiDevices []DeviceConfig `json:"midi_devices" yaml:"midi_devices"`
+	StateFile   string         `json:"state_file" yaml:"state_file"`
 }
 
 // Function validating the contents of user configuration
@@ -66,6 +197,14 @@ func (conf *UserConfig) Validate() error {
 				device.HoldDelta,
 			)
 		}
+		if device.TapWindow < 0 {
+			return fmt.Errorf(
+				"device #{%d} ({%s}): tap_window must be >=0ms. Now {%d} is provided",
+				idx,
+				device.DeviceName,
+				device.TapWindow,
+			)
+		}
 		if device.StartupDelay < 0 {
 			return fmt.Errorf(
 				"device #{%d} ({%s}): startup_delay must be >=0ms. Now {%d} is provided",
@@ -91,10 +230,553 @@ func (conf *UserConfig) Validate() error {
 				device.ReconnectInterval,
 			)
 		}
+		if err := validateControls(idx, device); err != nil {
+			return err
+		}
+		if err := validateControlFilters(idx, device); err != nil {
+			return err
+		}
+		if err := validateButtonControls(idx, device); err != nil {
+			return err
+		}
+		if err := validateToggleKeys(idx, device); err != nil {
+			return err
+		}
+		if err := validateKeyGroups(idx, device); err != nil {
+			return err
+		}
+		if err := validateChords(idx, device); err != nil {
+			return err
+		}
+		if err := validateSysExSignals(idx, device); err != nil {
+			return err
+		}
+		if err := validateHighResolutionControls(idx, device); err != nil {
+			return err
+		}
+		if err := validateHoldLevels(idx, device); err != nil {
+			return err
+		}
+		if err := validateKeyRepeats(idx, device); err != nil {
+			return err
+		}
+		if err := validateVelocityCurves(idx, device); err != nil {
+			return err
+		}
+		if err := validateKeyNames(idx, device); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Function validating the contents of accumulate controls configuration of single device
+func validateControls(idx int, device DeviceConfig) error {
+	feedbackOwners := make(map[int]int)
+	for controlsIdx, controls := range device.Controls {
+		for _, feedbackKey := range controls.Feedback.Keys {
+			if feedbackKey < 0 || feedbackKey > 127 {
+				return fmt.Errorf(
+					"device #{%d} ({%s}): accumulate controls #{%d} feedback key {%d} must be in range [0, 127]",
+					idx,
+					device.DeviceName,
+					controlsIdx,
+					feedbackKey,
+				)
+			}
+			for _, key := range controls.Keys {
+				if owner, has := feedbackOwners[feedbackKey]; has && owner != key {
+					return fmt.Errorf(
+						"device #{%d} ({%s}): feedback key {%d} is shared by controls {%d} and {%d}",
+						idx,
+						device.DeviceName,
+						feedbackKey,
+						owner,
+						key,
+					)
+				}
+				feedbackOwners[feedbackKey] = key
+			}
+		}
+		if controls.Rotate && controls.Passthrough {
+			return fmt.Errorf(
+				"device #{%d} ({%s}): accumulate controls #{%d} can't be both rotate and passthrough",
+				idx,
+				device.DeviceName,
+				controlsIdx,
+			)
+		}
+		if controls.ValueRange[0] > controls.ValueRange[1] {
+			return fmt.Errorf(
+				"device #{%d} ({%s}): accumulate controls #{%d} value_range must be ascending. Now {%v} is provided",
+				idx,
+				device.DeviceName,
+				controlsIdx,
+				controls.ValueRange,
+			)
+		}
+		switch controls.EncoderMode {
+		case "", EncoderTriggers, EncoderTwosComplement, EncoderSignedBit, EncoderBinaryOffset, EncoderAbsolute:
+		default:
+			return fmt.Errorf(
+				"device #{%d} ({%s}): accumulate controls #{%d} have unknown encoder_mode {%s}",
+				idx,
+				device.DeviceName,
+				controlsIdx,
+				controls.EncoderMode,
+			)
+		}
+		if len(controls.Feedback.Colors) > 0 && len(controls.Feedback.Keys) == 0 {
+			return fmt.Errorf(
+				"device #{%d} ({%s}): accumulate controls #{%d} feedback colors require feedback keys",
+				idx,
+				device.DeviceName,
+				controlsIdx,
+			)
+		}
+		if controls.Rotate && controls.EncoderMode == EncoderAbsolute {
+			return fmt.Errorf(
+				"device #{%d} ({%s}): accumulate controls #{%d} can't rotate with encoder_mode {%s}",
+				idx,
+				device.DeviceName,
+				controlsIdx,
+				EncoderAbsolute,
+			)
+		}
+		if controls.Pickup && controls.EncoderMode != EncoderAbsolute {
+			return fmt.Errorf(
+				"device #{%d} ({%s}): accumulate controls #{%d} pickup requires encoder_mode {%s}",
+				idx,
+				device.DeviceName,
+				controlsIdx,
+				EncoderAbsolute,
+			)
+		}
+		if controls.Step < 0 {
+			return fmt.Errorf(
+				"device #{%d} ({%s}): accumulate controls #{%d} step must be >=0. Now {%d} is provided",
+				idx,
+				device.DeviceName,
+				controlsIdx,
+				controls.Step,
+			)
+		}
+		for _, point := range controls.Acceleration {
+			if point.Interval <= 0 || point.Multiplier < 1 {
+				return fmt.Errorf(
+					"device #{%d} ({%s}): accumulate controls #{%d} acceleration must have interval >0ms and multiplier >=1. Now {%d} and {%d} are provided",
+					idx,
+					device.DeviceName,
+					controlsIdx,
+					point.Interval,
+					point.Multiplier,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// Function validating the contents of control filters configuration of single device
+func validateControlFilters(idx int, device DeviceConfig) error {
+	relative := make(map[int]struct{})
+	for _, controls := range device.Controls {
+		if controls.Passthrough || controls.EncoderMode == EncoderAbsolute {
+			continue
+		}
+		for _, key := range controls.Keys {
+			relative[key] = struct{}{}
+		}
+	}
+	keys := make(map[int]struct{})
+	for filterIdx, filter := range device.ControlFilters {
+		if len(filter.Keys) == 0 {
+			return fmt.Errorf("device #{%d} ({%s}): control filter #{%d} has no keys specified", idx, device.DeviceName, filterIdx)
+		}
+		for _, key := range filter.Keys {
+			if _, has := keys[key]; has {
+				return fmt.Errorf("device #{%d} ({%s}): control {%d} has several filters", idx, device.DeviceName, key)
+			}
+			if _, has := relative[key]; has {
+				return fmt.Errorf(
+					"device #{%d} ({%s}): control {%d} with relative encoder_mode can't be filtered",
+					idx,
+					device.DeviceName,
+					key,
+				)
+			}
+			keys[key] = struct{}{}
+		}
+		if filter.Deadband < 0 || filter.Hysteresis < 0 || filter.MovingAverage < 0 || filter.MaxRate < 0 {
+			return fmt.Errorf(
+				"device #{%d} ({%s}): control filter #{%d} deadband, hysteresis, moving_average and max_rate must be >=0",
+				idx,
+				device.DeviceName,
+				filterIdx,
+			)
+		}
+	}
+	return nil
+}
+
+// Function validating the contents of button controls configuration of single device
+func validateButtonControls(idx int, device DeviceConfig) error {
+	accumulated := make(map[int]struct{})
+	for _, controls := range device.Controls {
+		for _, key := range controls.Keys {
+			accumulated[key] = struct{}{}
+		}
+	}
+	filtered := make(map[int]struct{})
+	for _, filter := range device.ControlFilters {
+		for _, key := range filter.Keys {
+			filtered[key] = struct{}{}
+		}
+	}
+	highResolution := make(map[int]struct{})
+	for _, control := range device.HighResolution {
+		if control.Type == HighResolutionCC14 {
+			highResolution[control.MSB] = struct{}{}
+			highResolution[control.LSB] = struct{}{}
+		}
+	}
+	buttons := make(map[int]struct{})
+	for _, key := range device.ButtonControls {
+		if key < 0 || key > 127 {
+			return fmt.Errorf("device #{%d} ({%s}): button control {%d} must be in range [0, 127]", idx, device.DeviceName, key)
+		}
+		if _, has := buttons[key]; has {
+			return fmt.Errorf("device #{%d} ({%s}): found duplicate button control {%d}", idx, device.DeviceName, key)
+		}
+		if _, has := accumulated[key]; has {
+			return fmt.Errorf("device #{%d} ({%s}): control {%d} can't be both button and accumulate control", idx, device.DeviceName, key)
+		}
+		if _, has := filtered[key]; has {
+			return fmt.Errorf("device #{%d} ({%s}): control {%d} can't be both button and filtered control", idx, device.DeviceName, key)
+		}
+		if _, has := highResolution[key]; has {
+			return fmt.Errorf("device #{%d} ({%s}): control {%d} can't be both button and high resolution control", idx, device.DeviceName, key)
+		}
+		buttons[key] = struct{}{}
+	}
+	return nil
+}
+
+// Function validating the contents of toggle keys configuration of single device
+func validateToggleKeys(idx int, device DeviceConfig) error {
+	keys := make(map[int]struct{})
+	for toggleIdx, toggle := range device.ToggleKeys {
+		if len(toggle.Keys) == 0 {
+			return fmt.Errorf("device #{%d} ({%s}): toggle keys #{%d} have no keys specified", idx, device.DeviceName, toggleIdx)
+		}
+		for _, key := range toggle.Keys {
+			if key < 0 || key > 127 {
+				return fmt.Errorf("device #{%d} ({%s}): toggle key {%d} must be in range [0, 127]", idx, device.DeviceName, key)
+			}
+			if _, has := keys[key]; has {
+				return fmt.Errorf("device #{%d} ({%s}): found duplicate toggle key {%d}", idx, device.DeviceName, key)
+			}
+			keys[key] = struct{}{}
+		}
+	}
+	return nil
+}
+
+// Function validating the contents of key groups configuration of single device
+func validateKeyGroups(idx int, device DeviceConfig) error {
+	toggles := make(map[int]struct{})
+	for _, toggle := range device.ToggleKeys {
+		for _, key := range toggle.Keys {
+			toggles[key] = struct{}{}
+		}
+	}
+	names := make(map[string]struct{})
+	keys := make(map[int]struct{})
+	for groupIdx, group := range device.KeyGroups {
+		if group.Name == "" {
+			return fmt.Errorf("device #{%d} ({%s}): key group #{%d} has no name specified", idx, device.DeviceName, groupIdx)
+		}
+		if _, has := names[group.Name]; has {
+			return fmt.Errorf("device #{%d} ({%s}): found duplicate key group with name {%s}", idx, device.DeviceName, group.Name)
+		}
+		names[group.Name] = struct{}{}
+		if len(group.Keys) < 2 {
+			return fmt.Errorf(
+				"device #{%d} ({%s}): key group {%s} must contain at least 2 keys. Now {%d} is provided",
+				idx,
+				device.DeviceName,
+				group.Name,
+				len(group.Keys),
+			)
+		}
+		for _, key := range group.Keys {
+			if key < 0 || key > 127 {
+				return fmt.Errorf("device #{%d} ({%s}): key group {%s} key {%d} must be in range [0, 127]", idx, device.DeviceName, group.Name, key)
+			}
+			if _, has := keys[key]; has {
+				return fmt.Errorf("device #{%d} ({%s}): key {%d} belongs to several key groups", idx, device.DeviceName, key)
+			}
+			if _, has := toggles[key]; has {
+				return fmt.Errorf("device #{%d} ({%s}): key {%d} can't be both toggle key and key of group", idx, device.DeviceName, key)
+			}
+			keys[key] = struct{}{}
+		}
+	}
+	return nil
+}
+
+// Function validating the contents of chords configuration of single device
+func validateChords(idx int, device DeviceConfig) error {
+	names := make(map[string]struct{})
+	for chordIdx, chord := range device.Chords {
+		if chord.Name == "" {
+			return fmt.Errorf("device #{%d} ({%s}): chord #{%d} has no name specified", idx, device.DeviceName, chordIdx)
+		}
+		if _, has := names[chord.Name]; has {
+			return fmt.Errorf("device #{%d} ({%s}): found duplicate chord with name {%s}", idx, device.DeviceName, chord.Name)
+		}
+		names[chord.Name] = struct{}{}
+		if len(chord.Keys) < 2 {
+			return fmt.Errorf(
+				"device #{%d} ({%s}): chord {%s} must contain at least 2 keys. Now {%d} is provided",
+				idx,
+				device.DeviceName,
+				chord.Name,
+				len(chord.Keys),
+			)
+		}
+		for _, key := range chord.Keys {
+			if key < 0 || key > 127 {
+				return fmt.Errorf("device #{%d} ({%s}): chord {%s} key {%d} must be in range [0, 127]", idx, device.DeviceName, chord.Name, key)
+			}
+		}
+		if chord.Window < 0 {
+			return fmt.Errorf(
+				"device #{%d} ({%s}): window of chord {%s} must be >=0ms. Now {%d} is provided",
+				idx,
+				device.DeviceName,
+				chord.Name,
+				chord.Window,
+			)
+		}
+	}
+	return nil
+}
+
+// Function validating the contents of SysEx signals configuration of single device
+func validateSysExSignals(idx int, device DeviceConfig) error {
+	for sysExIdx, sysEx := range device.SysExSignals {
+		if sysEx.Name == "" {
+			return fmt.Errorf("device #{%d} ({%s}): sysex signal #{%d} has no name specified", idx, device.DeviceName, sysExIdx)
+		}
+		tokens := strings.Fields(sysEx.Pattern)
+		if len(tokens) == 0 {
+			return fmt.Errorf("device #{%d} ({%s}): sysex signal {%s} has empty pattern", idx, device.DeviceName, sysEx.Name)
+		}
+		for _, token := range tokens {
+			if token == "??" || token == "%%" || token == "%*" {
+				continue
+			}
+			if _, err := hex.DecodeString(token); err != nil || len(token) != 2 {
+				return fmt.Errorf(
+					"device #{%d} ({%s}): sysex signal {%s} has invalid pattern token {%s}",
+					idx,
+					device.DeviceName,
+					sysEx.Name,
+					token,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// Function validating the contents of high resolution controls configuration of single device
+func validateHighResolutionControls(idx int, device DeviceConfig) error {
+	for controlIdx, control := range device.HighResolution {
+		switch control.Type {
+		case HighResolutionCC14:
+			if control.MSB < 0 || control.MSB > 127 || control.LSB < 0 || control.LSB > 127 || control.MSB == control.LSB {
+				return fmt.Errorf(
+					"device #{%d} ({%s}): high resolution control #{%d} must have different msb and lsb in range [0, 127]. Now {%d} and {%d} are provided",
+					idx,
+					device.DeviceName,
+					controlIdx,
+					control.MSB,
+					control.LSB,
+				)
+			}
+		case HighResolutionNRPN, HighResolutionRPN:
+			if control.Parameter < 0 || control.Parameter > 16383 {
+				return fmt.Errorf(
+					"device #{%d} ({%s}): high resolution control #{%d} must have parameter in range [0, 16383]. Now {%d} is provided",
+					idx,
+					device.DeviceName,
+					controlIdx,
+					control.Parameter,
+				)
+			}
+		default:
+			return fmt.Errorf(
+				"device #{%d} ({%s}): high resolution control #{%d} has unknown type {%s}",
+				idx,
+				device.DeviceName,
+				controlIdx,
+				control.Type,
+			)
+		}
 	}
 	return nil
 }
 
+// Function validating the contents of hold levels configuration of single device
+func validateHoldLevels(idx int, device DeviceConfig) error {
+	for levelIdx, level := range device.HoldLevels {
+		if level.Name == "" {
+			return fmt.Errorf("device #{%d} ({%s}): hold level #{%d} has no name specified", idx, device.DeviceName, levelIdx)
+		}
+		if level.Delta <= 0 {
+			return fmt.Errorf(
+				"device #{%d} ({%s}): hold level {%s} delta must be >0ms. Now {%d} is provided",
+				idx,
+				device.DeviceName,
+				level.Name,
+				level.Delta,
+			)
+		}
+		if len(level.KeyRange) != 0 && (len(level.KeyRange) != 2 || level.KeyRange[0] > level.KeyRange[1] ||
+			level.KeyRange[0] < 0 || level.KeyRange[1] > 127) {
+			return fmt.Errorf(
+				"device #{%d} ({%s}): hold level {%s} key_range must be ascending pair in range [0, 127]. Now {%v} is provided",
+				idx,
+				device.DeviceName,
+				level.Name,
+				level.KeyRange,
+			)
+		}
+	}
+	return nil
+}
+
+// Function validating the contents of auto-repeat configuration of single device
+func validateKeyRepeats(idx int, device DeviceConfig) error {
+	for repeatIdx, repeat := range device.Repeat {
+		if len(repeat.KeyRange) != 0 && (len(repeat.KeyRange) != 2 || repeat.KeyRange[0] > repeat.KeyRange[1] ||
+			repeat.KeyRange[0] < 0 || repeat.KeyRange[1] > 127) {
+			return fmt.Errorf(
+				"device #{%d} ({%s}): repeat #{%d} key_range must be ascending pair in range [0, 127]. Now {%v} is provided",
+				idx,
+				device.DeviceName,
+				repeatIdx,
+				repeat.KeyRange,
+			)
+		}
+		if repeat.Interval <= 0 {
+			return fmt.Errorf(
+				"device #{%d} ({%s}): repeat #{%d} interval must be >0ms. Now {%d} is provided",
+				idx,
+				device.DeviceName,
+				repeatIdx,
+				repeat.Interval,
+			)
+		}
+	}
+	return nil
+}
+
+// Function validating the contents of velocity curves configuration of single device
+func validateVelocityCurves(idx int, device DeviceConfig) error {
+	for curveIdx, curve := range device.VelocityCurves {
+		if len(curve.KeyRange) != 0 && (len(curve.KeyRange) != 2 || curve.KeyRange[0] > curve.KeyRange[1] ||
+			curve.KeyRange[0] < 0 || curve.KeyRange[1] > 127) {
+			return fmt.Errorf(
+				"device #{%d} ({%s}): velocity curve #{%d} key_range must be ascending pair in range [0, 127]. Now {%v} is provided",
+				idx,
+				device.DeviceName,
+				curveIdx,
+				curve.KeyRange,
+			)
+		}
+		switch curve.Type {
+		case VelocityLinear, VelocityLogarithmic, VelocityExponential:
+		case VelocityFixed:
+			if curve.Value < 1 || curve.Value > 127 {
+				return fmt.Errorf(
+					"device #{%d} ({%s}): velocity curve #{%d} value must be in range [1, 127]. Now {%d} is provided",
+					idx,
+					device.DeviceName,
+					curveIdx,
+					curve.Value,
+				)
+			}
+		case VelocityCustom:
+			if len(curve.Table) < 2 {
+				return fmt.Errorf(
+					"device #{%d} ({%s}): velocity curve #{%d} table must contain at least 2 values. Now {%d} is provided",
+					idx,
+					device.DeviceName,
+					curveIdx,
+					len(curve.Table),
+				)
+			}
+			for _, value := range curve.Table {
+				if value < 0 || value > 127 {
+					return fmt.Errorf(
+						"device #{%d} ({%s}): velocity curve #{%d} table values must be in range [0, 127]. Now {%d} is provided",
+						idx,
+						device.DeviceName,
+						curveIdx,
+						value,
+					)
+				}
+			}
+		default:
+			return fmt.Errorf(
+				"device #{%d} ({%s}): velocity curve #{%d} has unknown type {%s}",
+				idx,
+				device.DeviceName,
+				curveIdx,
+				curve.Type,
+			)
+		}
+	}
+	return nil
+}
+
+// Function validating the contents of key names configuration of single device
+func validateKeyNames(idx int, device DeviceConfig) error {
+	if err := validateNames(idx, device, device.KeyNames, "key"); err != nil {
+		return err
+	}
+	return validateNames(idx, device, device.ControlNames, "control")
+}
+
+// Function validating logical names of single kind of keys of single device
+func validateNames(idx int, device DeviceConfig, keyNames map[int]string, kind string) error {
+	keys := make(map[string]int)
+	for key, name := range keyNames {
+		if key < 0 || key > 127 {
+			return fmt.Errorf("device #{%d} ({%s}): named %s {%d} must be in range [0, 127]", idx, device.DeviceName, kind, key)
+		}
+		if name == "" {
+			return fmt.Errorf("device #{%d} ({%s}): %s {%d} has empty name", idx, device.DeviceName, kind, key)
+		}
+		if other, has := keys[name]; has {
+			return fmt.Errorf(
+				"device #{%d} ({%s}): found duplicate %s name {%s} for %ss {%d} and {%d}",
+				idx,
+				device.DeviceName,
+				kind,
+				name,
+				kind,
+				min(key, other),
+				max(key, other),
+			)
+		}
+		keys[name] = key
+	}
+	return nil
+}
 
 // Function seraching duplicate device name in array of configured MIDI-devices
 func (conf *UserConfig) hasDuplicateDevices() (string, bool) {
@@ -0,0 +1,226 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+// Function returns minimal valid user configuration with given accumulate controls
+func testUserConfig(controls ...Controls) *UserConfig {
+	return &UserConfig{MidiDevices: []DeviceConfig{{
+		DeviceName:        "test",
+		ReconnectInterval: MinReconnectIntervalMs,
+		Namespace:         "default",
+		Controls:          controls,
+	}}}
+}
+
+// Function checks validation of accumulate controls modes and ranges
+func TestValidateControls(t *testing.T) {
+	cases := []struct {
+		controls Controls
+		err      string
+	}{
+		{Controls{Keys: []int{1}, ValueRange: [2]int{0, 127}}, ""},
+		{Controls{Keys: []int{1}, ValueRange: [2]int{0, 127}, Rotate: true}, ""},
+		{Controls{Keys: []int{1}, Passthrough: true}, ""},
+		{Controls{Keys: []int{1}, ValueRange: [2]int{0, 127}, Rotate: true, Passthrough: true}, "both rotate and passthrough"},
+		{Controls{Keys: []int{1}, ValueRange: [2]int{127, 0}}, "value_range must be ascending"},
+		{Controls{Keys: []int{1}, EncoderMode: "unknown"}, "unknown encoder_mode"},
+		{Controls{Keys: []int{1}, ValueRange: [2]int{0, 127}, Rotate: true, EncoderMode: EncoderAbsolute}, "can't rotate with encoder_mode {absolute}"},
+		{Controls{Keys: []int{1}, ValueRange: [2]int{0, 127}, Feedback: ControlFeedback{Keys: []int{40, 41}}}, ""},
+		{Controls{Keys: []int{1}, ValueRange: [2]int{0, 127}, Feedback: ControlFeedback{Keys: []int{200}}}, "feedback key {200} must be in range [0, 127]"},
+		{Controls{Keys: []int{1, 2}, ValueRange: [2]int{0, 127}, Feedback: ControlFeedback{Keys: []int{40}}}, "feedback key {40} is shared by controls {1} and {2}"},
+	}
+	for _, c := range cases {
+		err := testUserConfig(c.controls).Validate()
+		if c.err == "" && err != nil {
+			t.Fatalf("unexpected error for %+v: %v", c.controls, err)
+		}
+		if c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)) {
+			t.Fatalf("expected error containing {%s} for %+v, got %v", c.err, c.controls, err)
+		}
+	}
+}
+
+// Function checks validation of key names
+func TestValidateKeyNames(t *testing.T) {
+	cases := []struct {
+		keyNames map[int]string
+		err      string
+	}{
+		{map[int]string{94: "play", 93: "stop"}, ""},
+		{map[int]string{94: ""}, "empty name"},
+		{map[int]string{94: "play", 95: "play"}, "duplicate key name {play} for keys {94} and {95}"},
+		{map[int]string{300: "play"}, "named key {300} must be in range [0, 127]"},
+	}
+	for _, c := range cases {
+		userConfig := testUserConfig()
+		userConfig.MidiDevices[0].KeyNames = c.keyNames
+		err := userConfig.Validate()
+		if c.err == "" && err != nil {
+			t.Fatalf("unexpected error for %+v: %v", c.keyNames, err)
+		}
+		if c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)) {
+			t.Fatalf("expected error containing {%s} for %+v, got %v", c.err, c.keyNames, err)
+		}
+	}
+}
+
+// Function checks validation of control names scoped separately from key names
+func TestValidateControlNames(t *testing.T) {
+	userConfig := testUserConfig()
+	userConfig.MidiDevices[0].KeyNames = map[int]string{64: "sustain"}
+	userConfig.MidiDevices[0].ControlNames = map[int]string{64: "sustain"}
+	if err := userConfig.Validate(); err != nil {
+		t.Fatalf("expected same name for note and control, got %v", err)
+	}
+	userConfig.MidiDevices[0].ControlNames = map[int]string{128: "sustain"}
+	if err := userConfig.Validate(); err == nil || !strings.Contains(err.Error(), "named control {128} must be in range [0, 127]") {
+		t.Fatalf("expected out of range control name to be rejected, got %v", err)
+	}
+}
+
+// Function checks validation of control filters
+func TestValidateControlFilters(t *testing.T) {
+	cases := []struct {
+		filters []ControlFilter
+		err     string
+	}{
+		{[]ControlFilter{{Keys: []int{7}, Deadband: 1, MaxRate: 50}}, ""},
+		{[]ControlFilter{{Deadband: 1}}, "has no keys"},
+		{[]ControlFilter{{Keys: []int{7}}, {Keys: []int{7}}}, "several filters"},
+		{[]ControlFilter{{Keys: []int{7}, MovingAverage: -1}}, "must be >=0"},
+		{[]ControlFilter{{Keys: []int{16}, MaxRate: 50}}, ""},
+		{[]ControlFilter{{Keys: []int{17}, MaxRate: 50}}, "relative encoder_mode can't be filtered"},
+	}
+	for _, c := range cases {
+		userConfig := testUserConfig(
+			Controls{Keys: []int{16}, ValueRange: [2]int{0, 127}, EncoderMode: EncoderAbsolute},
+			Controls{Keys: []int{17}, ValueRange: [2]int{0, 127}, EncoderMode: EncoderBinaryOffset},
+		)
+		userConfig.MidiDevices[0].ControlFilters = c.filters
+		err := userConfig.Validate()
+		if c.err == "" && err != nil {
+			t.Fatalf("unexpected error for %+v: %v", c.filters, err)
+		}
+		if c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)) {
+			t.Fatalf("expected error containing {%s} for %+v, got %v", c.err, c.filters, err)
+		}
+	}
+}
+
+// Function checks that feedback keys of different accumulate controls don't overlap
+func TestValidateControlFeedbackOverlap(t *testing.T) {
+	first := Controls{Keys: []int{1}, ValueRange: [2]int{0, 127}, Feedback: ControlFeedback{Keys: []int{40, 41}}}
+	second := Controls{Keys: []int{2}, ValueRange: [2]int{0, 127}, Feedback: ControlFeedback{Keys: []int{41, 42}}}
+	err := testUserConfig(first, second).Validate()
+	if err == nil || !strings.Contains(err.Error(), "feedback key {41} is shared by controls {1} and {2}") {
+		t.Fatalf("expected overlapping feedback keys to be rejected, got %v", err)
+	}
+}
+
+// Function checks that button controls don't overlap with other kinds of controls
+func TestValidateButtonControls(t *testing.T) {
+	cases := []struct {
+		device DeviceConfig
+		err    string
+	}{
+		{DeviceConfig{ButtonControls: []int{64}}, ""},
+		{DeviceConfig{ButtonControls: []int{128}}, "button control {128} must be in range [0, 127]"},
+		{DeviceConfig{ButtonControls: []int{64, 64}}, "duplicate button control {64}"},
+		{DeviceConfig{ButtonControls: []int{64}, Controls: []Controls{{Keys: []int{64}, Passthrough: true}}},
+			"can't be both button and accumulate control"},
+		{DeviceConfig{ButtonControls: []int{64}, ControlFilters: []ControlFilter{{Keys: []int{64}, Deadband: 1}}},
+			"can't be both button and filtered control"},
+		{DeviceConfig{ButtonControls: []int{39}, HighResolution: []HighResolutionControl{{Type: HighResolutionCC14, MSB: 7, LSB: 39}}},
+			"can't be both button and high resolution control"},
+	}
+	for _, c := range cases {
+		userConfig := testUserConfig()
+		userConfig.MidiDevices[0].ButtonControls = c.device.ButtonControls
+		userConfig.MidiDevices[0].Controls = c.device.Controls
+		userConfig.MidiDevices[0].ControlFilters = c.device.ControlFilters
+		userConfig.MidiDevices[0].HighResolution = c.device.HighResolution
+		err := userConfig.Validate()
+		if c.err == "" && err != nil {
+			t.Fatalf("unexpected error for %+v: %v", c.device, err)
+		}
+		if c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)) {
+			t.Fatalf("expected error containing {%s} for %+v, got %v", c.err, c.device, err)
+		}
+	}
+}
+
+// Function checks validation of toggle keys
+func TestValidateToggleKeys(t *testing.T) {
+	cases := []struct {
+		toggle ToggleKeys
+		err    string
+	}{
+		{ToggleKeys{Keys: []int{40, 41}}, ""},
+		{ToggleKeys{}, "have no keys specified"},
+		{ToggleKeys{Keys: []int{40, 40}}, "duplicate toggle key {40}"},
+		{ToggleKeys{Keys: []int{40, 200}}, "toggle key {200} must be in range [0, 127]"},
+		{ToggleKeys{Keys: []int{-1}}, "toggle key {-1} must be in range [0, 127]"},
+	}
+	for _, c := range cases {
+		userConfig := testUserConfig()
+		userConfig.MidiDevices[0].ToggleKeys = []ToggleKeys{c.toggle}
+		err := userConfig.Validate()
+		if c.err == "" && err != nil {
+			t.Fatalf("unexpected error for %+v: %v", c.toggle, err)
+		}
+		if c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)) {
+			t.Fatalf("expected error containing {%s} for %+v, got %v", c.err, c.toggle, err)
+		}
+	}
+}
+
+// Function checks validation of key groups
+func TestValidateKeyGroups(t *testing.T) {
+	cases := []struct {
+		group KeyGroup
+		err   string
+	}{
+		{KeyGroup{Name: "scene", Keys: []int{54, 55}}, ""},
+		{KeyGroup{Keys: []int{54, 55}}, "has no name specified"},
+		{KeyGroup{Name: "scene", Keys: []int{54}}, "at least 2 keys"},
+		{KeyGroup{Name: "scene", Keys: []int{54, 200}}, "key group {scene} key {200} must be in range [0, 127]"},
+	}
+	for _, c := range cases {
+		userConfig := testUserConfig()
+		userConfig.MidiDevices[0].KeyGroups = []KeyGroup{c.group}
+		err := userConfig.Validate()
+		if c.err == "" && err != nil {
+			t.Fatalf("unexpected error for %+v: %v", c.group, err)
+		}
+		if c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)) {
+			t.Fatalf("expected error containing {%s} for %+v, got %v", c.err, c.group, err)
+		}
+	}
+}
+
+// Function checks validation of chords
+func TestValidateChords(t *testing.T) {
+	cases := []struct {
+		chord Chord
+		err   string
+	}{
+		{Chord{Name: "panic", Keys: []int{36, 37}}, ""},
+		{Chord{Name: "panic", Keys: []int{36}}, "at least 2 keys"},
+		{Chord{Name: "panic", Keys: []int{36, 300}}, "key {300} must be in range [0, 127]"},
+		{Chord{Name: "panic", Keys: []int{-1, 36}}, "key {-1} must be in range [0, 127]"},
+	}
+	for _, c := range cases {
+		userConfig := testUserConfig()
+		userConfig.MidiDevices[0].Chords = []Chord{c.chord}
+		err := userConfig.Validate()
+		if c.err == "" && err != nil {
+			t.Fatalf("unexpected error for %+v: %v", c.chord, err)
+		}
+		if c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)) {
+			t.Fatalf("expected error containing {%s} for %+v, got %v", c.err, c.chord, err)
+		}
+	}
+}
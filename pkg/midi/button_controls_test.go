@@ -0,0 +1,40 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/model"
+	"testing"
+	"time"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// Function checks that control configured as button goes through push, hold and release lifecycle
+func TestButtonControls(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.ButtonControls = []int{20}
+	md, fc, signals := newTestDevice(t, conf)
+
+	md.processMidiMessage(midi.ControlChange(0, 20, 127), 0)
+	md.processMidiMessage(midi.ControlChange(0, 20, 0), 0)
+
+	md.processMidiMessage(midi.ControlChange(0, 20, 127), 0)
+	md.processMidiMessage(midi.ControlChange(0, 20, 127), 0)
+	fc.Advance(1500 * time.Millisecond)
+	md.processMidiMessage(midi.ControlChange(0, 20, 0), 0)
+
+	md.processMidiMessage(midi.ControlChange(0, 21, 127), 0)
+	md.processMidiMessage(midi.NoteOn(0, 20, 100), 0)
+
+	received := drainSignals(signals)
+	expectSignalCodes(t, received,
+		"NotePushed", "NoteReleased", "NotePushed", "NoteHold", "NoteReleasedAfterHold", "ControlPushed", "NotePushed")
+	if pushed := received[0].(model.NotePushed); pushed.KeyCode != 20 || pushed.Velocity != 127 || pushed.Kind != model.KeyKindControl {
+		t.Fatalf("expected control button 20 pushed with velocity 127, got %+v", pushed)
+	}
+	if held := received[3].(model.NoteHold); held.Kind != model.KeyKindControl {
+		t.Fatalf("expected hold of control button, got %+v", held)
+	}
+	if pushed := received[6].(model.NotePushed); pushed.KeyCode != 20 || pushed.Kind != model.KeyKindNote {
+		t.Fatalf("expected note 20 pushed, got %+v", pushed)
+	}
+}
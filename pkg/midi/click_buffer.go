@@ -1,14 +1,61 @@
 package midi
 
-type ClickBuffer map[uint8]*KeyContext
+import (
+	"midi_manipulator/pkg/model"
+	"sort"
+)
+
+// Representation of kind of MIDI message that produced key context
+type KeyKind uint8
+
+const (
+	NoteKind KeyKind = iota
+	ControlKind
+)
+
+// Function returns name of key kind reported in signals
+func (kind KeyKind) String() string {
+	if kind == ControlKind {
+		return model.KeyKindControl
+	}
+	return model.KeyKindNote
+}
+
+// Representation of key identifiers separating key spaces of message kinds and channels
+type KeyIdentifiers struct {
+	kind    KeyKind
+	channel uint8
+	key     uint8
+}
+
+type ClickBuffer map[KeyIdentifiers]*KeyContext
 
 // Function returns context of key contained in click buffer by id
-func (cb ClickBuffer) GetKeyContext(key uint8) (*KeyContext, bool) {
-	val, ok := cb[key]
+func (cb ClickBuffer) GetKeyContext(id KeyIdentifiers) (*KeyContext, bool) {
+	val, ok := cb[id]
 	return val, ok
 }
 
 // Function sets context of key contained in click buffer by id and current context
-func (cb ClickBuffer) SetKeyContext(key uint8, midiKey KeyContext) {
-	cb[key] = &midiKey
+func (cb ClickBuffer) SetKeyContext(id KeyIdentifiers, midiKey KeyContext) {
+	cb[id] = &midiKey
+}
+
+// Function returns contexts of keys contained in click buffer ordered by kind, channel and key
+func (cb ClickBuffer) sortedKeyContexts() []*KeyContext {
+	contexts := make([]*KeyContext, 0, len(cb))
+	for _, kctx := range cb {
+		contexts = append(contexts, kctx)
+	}
+	sort.Slice(contexts, func(i, j int) bool {
+		a, b := contexts[i].id, contexts[j].id
+		if a.kind != b.kind {
+			return a.kind < b.kind
+		}
+		if a.channel != b.channel {
+			return a.channel < b.channel
+		}
+		return a.key < b.key
+	})
+	return contexts
 }
@@ -0,0 +1,29 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/model"
+	"testing"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// Function checks that notes and controls with same number on different channels do not share state
+func TestClickBufferSeparatesKeySpaces(t *testing.T) {
+	md, _, signals := newTestDevice(t, testDeviceConfig())
+
+	md.processMidiMessage(midi.NoteOn(0, 16, 100), 0)
+	md.processMidiMessage(midi.NoteOn(1, 16, 90), 0)
+	md.processMidiMessage(midi.ControlChange(0, 16, 5), 0)
+	expectSignalCodes(t, drainSignals(signals), "NotePushed", "NotePushed", "ControlPushed")
+
+	md.processMidiMessage(midi.NoteOff(1, 16), 0)
+	received := drainSignals(signals)
+	expectSignalCodes(t, received, "NoteReleased")
+	if released := received[0].(model.NoteReleased); released.Channel != 1 || released.Velocity != 90 {
+		t.Fatalf("unexpected release signal %+v", released)
+	}
+
+	if _, ok := md.clickBuffer.GetKeyContext(KeyIdentifiers{NoteKind, 0, 16}); !ok {
+		t.Fatalf("note on channel 0 was removed by release on channel 1")
+	}
+}
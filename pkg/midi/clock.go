@@ -0,0 +1,27 @@
+package midi
+
+import "time"
+
+// Representation of time source used by device timers
+type clock interface {
+	Now() time.Time
+	AfterFunc(d time.Duration, f func()) timer
+}
+
+// Representation of timer created by clock
+type timer interface {
+	Stop() bool
+}
+
+// Representation of clock entity backed by system time
+type systemClock struct{}
+
+// Function returns current system time
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Function calls f in its own goroutine after duration elapses
+func (systemClock) AfterFunc(d time.Duration, f func()) timer {
+	return time.AfterFunc(d, f)
+}
@@ -0,0 +1,80 @@
+package midi
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Representation of manually advanced clock used in tests
+type fakeClock struct {
+	mutex  sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// Representation of timer created by fake clock
+type fakeTimer struct {
+	clock    *fakeClock
+	deadline time.Time
+	callback func()
+}
+
+// Function initializes fake clock entity with arbitrary start time
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+// Function returns current fake time
+func (c *fakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+// Function registers callback to be fired when fake time passes duration
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) timer {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	t := &fakeTimer{clock: c, deadline: c.now.Add(d), callback: f}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+// Function moves fake time forward and synchronously fires due timers in deadline order
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	target := c.now.Add(d)
+	c.mutex.Unlock()
+
+	for {
+		c.mutex.Lock()
+		sort.SliceStable(c.timers, func(i, j int) bool {
+			return c.timers[i].deadline.Before(c.timers[j].deadline)
+		})
+		if len(c.timers) == 0 || c.timers[0].deadline.After(target) {
+			c.now = target
+			c.mutex.Unlock()
+			return
+		}
+		t := c.timers[0]
+		c.timers = c.timers[1:]
+		c.now = t.deadline
+		c.mutex.Unlock()
+
+		t.callback()
+	}
+}
+
+// Function prevents timer from firing and reports whether it was pending
+func (t *fakeTimer) Stop() bool {
+	t.clock.mutex.Lock()
+	defer t.clock.mutex.Unlock()
+	for idx, pending := range t.clock.timers {
+		if pending == t {
+			t.clock.timers = append(t.clock.timers[:idx], t.clock.timers[idx+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/model"
+	"sort"
+)
+
+// Function shows accumulated value of control on its feedback keys
+func (md *MidiDevice) showControlFeedback(control *Control) {
+	keys := control.Feedback.Keys
+	if len(keys) == 0 || md.backlightConfig == nil {
+		return
+	}
+
+	low, high := control.ValueRange[0], control.ValueRange[1]
+	value := max(low, min(control.InitialValue, high))
+	span := max(high-low, 1)
+
+	if len(keys) == 1 && len(control.Feedback.Colors) > 0 {
+		// SINGLE KEY WITH COLOR GRADIENT
+		colors := control.Feedback.Colors
+		color := colors[(value-low)*(len(colors)-1)/span]
+		md.turnLightOn(model.TurnLightOnCommand{KeyCode: keys[0], ColorName: color}, md.backlightConfig)
+		return
+	}
+
+	// RING OR BAR OF KEYS
+	var color string
+	if len(control.Feedback.Colors) > 0 {
+		color = control.Feedback.Colors[0]
+	}
+	lit := (value - low) * len(keys) / span
+	for idx, key := range keys {
+		if idx < lit {
+			md.turnLightOn(model.TurnLightOnCommand{KeyCode: key, ColorName: color}, md.backlightConfig)
+		} else {
+			md.turnLightOff(model.TurnLightOffCommand{KeyCode: key}, md.backlightConfig)
+		}
+	}
+}
+
+// Function shows accumulated values of all controls on their feedback keys ordered by key
+func (md *MidiDevice) showAllControlFeedback() {
+	keys := make([]int, 0, len(md.controls))
+	for key := range md.controls {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+
+	for _, key := range keys {
+		md.showControlFeedback(md.controls[key])
+	}
+}
@@ -0,0 +1,57 @@
+package midi
+
+import (
+	"bytes"
+	"midi_manipulator/pkg/config"
+	"testing"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// Function checks that sent messages equal expected ones
+func expectSent(t *testing.T, sent [][]byte, expected ...[]byte) {
+	t.Helper()
+	if len(sent) != len(expected) {
+		t.Fatalf("expected %d messages % X, got %d: % X", len(expected), expected, len(sent), sent)
+	}
+	for idx := range expected {
+		if !bytes.Equal(sent[idx], expected[idx]) {
+			t.Fatalf("message #%d: expected % X, got % X", idx, expected[idx], sent[idx])
+		}
+	}
+}
+
+// Function checks that accumulated value is shown on bar of feedback keys
+func TestControlFeedbackBar(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.Controls = []config.Controls{{
+		Keys:        []int{16},
+		ValueRange:  [2]int{0, 4},
+		EncoderMode: config.EncoderBinaryOffset,
+		Feedback:    config.ControlFeedback{Keys: []int{0, 1, 2, 3}, Colors: []string{"green"}},
+	}}
+	md, _, _ := newTestDevice(t, conf)
+	out := attachTestBacklight(t, md)
+
+	md.processMidiMessage(midi.ControlChange(0, 16, 66), 0)
+	expectSent(t, out.drain(),
+		[]byte{0x90, 0, 0x02}, []byte{0x90, 1, 0x02}, []byte{0x80, 2, 0x00}, []byte{0x80, 3, 0x00})
+}
+
+// Function checks that accumulated value is shown as color gradient of single feedback key
+func TestControlFeedbackGradient(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.Controls = []config.Controls{{
+		Keys:        []int{16},
+		ValueRange:  [2]int{0, 100},
+		EncoderMode: config.EncoderAbsolute,
+		Feedback:    config.ControlFeedback{Keys: []int{5}, Colors: []string{"blue", "green", "red"}},
+	}}
+	md, _, _ := newTestDevice(t, conf)
+	out := attachTestBacklight(t, md)
+
+	md.processMidiMessage(midi.ControlChange(0, 16, 10), 0)
+	md.processMidiMessage(midi.ControlChange(0, 16, 60), 0)
+	md.processMidiMessage(midi.ControlChange(0, 16, 100), 0)
+	expectSent(t, out.drain(), []byte{0x90, 5, 0x03}, []byte{0x90, 5, 0x02}, []byte{0x90, 5, 0x01})
+}
@@ -0,0 +1,143 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/config"
+	"time"
+)
+
+// Representation of filter context of single noisy control
+type controlFilter struct {
+	deadband   int
+	hysteresis int
+	window     int
+	interval   time.Duration
+	samples    []int
+	lastValue  int
+	direction  int
+	emittedAt  time.Time
+	pending    bool
+	channel    uint8
+	value      uint8
+	timer      timer
+}
+
+// Function applies configuration of control filters to MIDI-device entity
+func (md *MidiDevice) applyControlFilters(filters []config.ControlFilter) {
+	md.stopControlFilters()
+	md.controlFilters = make(map[int]*controlFilter)
+	for _, filter := range filters {
+		var interval time.Duration
+		if filter.MaxRate > 0 {
+			interval = time.Second / time.Duration(filter.MaxRate)
+		}
+		for _, key := range filter.Keys {
+			md.controlFilters[key] = &controlFilter{
+				deadband:   filter.Deadband,
+				hysteresis: filter.Hysteresis,
+				window:     filter.MovingAverage,
+				interval:   interval,
+				lastValue:  -1,
+			}
+		}
+	}
+}
+
+// Function stops delayed emissions of all control filters
+func (md *MidiDevice) stopControlFilters() {
+	for _, filter := range md.controlFilters {
+		if filter.timer != nil {
+			filter.timer.Stop()
+			filter.timer = nil
+		}
+	}
+}
+
+// Function smoothes value with moving average and returns false if change is within deadband or hysteresis
+func (f *controlFilter) apply(value int) (int, bool) {
+	if f.window > 1 {
+		f.samples = append(f.samples, value)
+		if len(f.samples) > f.window {
+			f.samples = f.samples[1:]
+		}
+		sum := 0
+		for _, sample := range f.samples {
+			sum += sample
+		}
+		value = (sum + len(f.samples)/2) / len(f.samples)
+	}
+	if f.deadband == 0 && f.hysteresis == 0 && f.window <= 1 {
+		return value, true // repeated values kept without smoothing filters
+	}
+	if f.lastValue < 0 {
+		f.lastValue = value
+		return value, true
+	}
+
+	diff := value - f.lastValue
+	direction := 1
+	if diff < 0 {
+		diff, direction = -diff, -1
+	}
+	if diff == 0 || diff <= f.deadband {
+		return value, false // change within deadband banned
+	}
+	if f.direction != 0 && direction != f.direction && diff <= f.hysteresis {
+		return value, false // reversal within hysteresis banned
+	}
+	f.lastValue = value
+	f.direction = direction
+	return value, true
+}
+
+// Function filters value of control and returns false if value must not be emitted now
+func (md *MidiDevice) filterControl(channel uint8, key uint8, velocity uint8) (uint8, bool) {
+	filter, ok := md.controlFilters[int(key)]
+	if !ok {
+		return velocity, true
+	}
+	value, accepted := filter.apply(int(velocity))
+	if !accepted {
+		return velocity, false
+	}
+	if filter.interval == 0 {
+		return uint8(value), true
+	}
+
+	now := md.clock.Now()
+	if filter.emittedAt.IsZero() || now.Sub(filter.emittedAt) >= filter.interval {
+		filter.emittedAt = now
+		filter.pending = false
+		return uint8(value), true
+	}
+	// COALESCE TO LAST VALUE UNTIL RATE ALLOWS EMISSION
+	filter.pending = true
+	filter.channel = channel
+	filter.value = uint8(value)
+	if filter.timer == nil {
+		filter.timer = md.clock.AfterFunc(filter.emittedAt.Add(filter.interval).Sub(now), func() {
+			md.flushControl(key, filter)
+		})
+	}
+	return velocity, false
+}
+
+// Function emits last coalesced value of control when rate limit interval elapsed
+func (md *MidiDevice) flushControl(key uint8, filter *controlFilter) {
+	md.mutex.Lock()
+	if md.controlFilters[int(key)] != filter {
+		md.mutex.Unlock()
+		return
+	}
+	filter.timer = nil
+	if !filter.pending {
+		md.mutex.Unlock()
+		return
+	}
+	filter.pending = false
+	filter.emittedAt = md.clock.Now()
+	md.pushControl(filter.channel, key, filter.value)
+	stamped := md.stampSignals(md.messageToSignal(), filter.emittedAt)
+	md.mutex.Unlock()
+
+	md.sendSignals(stamped)
+}
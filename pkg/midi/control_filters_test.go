@@ -0,0 +1,99 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/config"
+	"midi_manipulator/pkg/model"
+	"testing"
+	"time"
+
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// Function sends sequence of control values and returns values of emitted signals
+func controlValues(md *MidiDevice, signals chan core.Signal, key uint8, values ...uint8) []int {
+	for _, value := range values {
+		md.processMidiMessage(midi.ControlChange(0, key, value), 0)
+	}
+	return pushedValues(drainSignals(signals))
+}
+
+// Function returns values of received ControlPushed signals
+func pushedValues(received []core.Signal) []int {
+	var values []int
+	for _, signal := range received {
+		if pushed, ok := signal.(model.ControlPushed); ok {
+			values = append(values, pushed.Value)
+		}
+	}
+	return values
+}
+
+// Function checks that values are equal to expected ones
+func expectValues(t *testing.T, values []int, expected ...int) {
+	t.Helper()
+	if len(values) != len(expected) {
+		t.Fatalf("expected values %v, got %v", expected, values)
+	}
+	for idx := range expected {
+		if values[idx] != expected[idx] {
+			t.Fatalf("expected values %v, got %v", expected, values)
+		}
+	}
+}
+
+// Function checks that changes within deadband are not emitted
+func TestControlFilterDeadband(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.ControlFilters = []config.ControlFilter{{Keys: []int{7}, Deadband: 1}}
+	md, _, signals := newTestDevice(t, conf)
+
+	expectValues(t, controlValues(md, signals, 7, 64, 65, 63, 64, 66, 67, 68), 64, 66, 68)
+}
+
+// Function checks that reversal of direction within hysteresis is not emitted
+func TestControlFilterHysteresis(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.ControlFilters = []config.ControlFilter{{Keys: []int{7}, Hysteresis: 1}}
+	md, _, signals := newTestDevice(t, conf)
+
+	expectValues(t, controlValues(md, signals, 7, 64, 65, 64, 65, 66, 65, 64, 63), 64, 65, 66, 64, 63)
+}
+
+// Function checks that values are smoothed with moving average
+func TestControlFilterMovingAverage(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.ControlFilters = []config.ControlFilter{{Keys: []int{7}, MovingAverage: 4}}
+	md, _, signals := newTestDevice(t, conf)
+
+	expectValues(t, controlValues(md, signals, 7, 60, 64, 60, 64, 100), 60, 62, 61, 62, 72)
+}
+
+// Function checks that values exceeding max rate are coalesced to last one
+func TestControlFilterMaxRate(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.ControlFilters = []config.ControlFilter{{Keys: []int{7}, MaxRate: 10}}
+	md, fc, signals := newTestDevice(t, conf)
+
+	expectValues(t, controlValues(md, signals, 7, 10, 20, 30, 40), 10)
+	fc.Advance(100 * time.Millisecond)
+	expectValues(t, pushedValues(drainSignals(signals)), 40)
+
+	fc.Advance(200 * time.Millisecond)
+	expectValues(t, controlValues(md, signals, 7, 50), 50)
+	fc.Advance(200 * time.Millisecond)
+	expectValues(t, pushedValues(drainSignals(signals)))
+}
+
+// Function checks that repeated values pass when only max rate is configured
+func TestControlFilterMaxRateKeepsRepeatedValues(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.ControlFilters = []config.ControlFilter{{Keys: []int{7}, MaxRate: 10}}
+	md, fc, signals := newTestDevice(t, conf)
+
+	expectValues(t, controlValues(md, signals, 7, 1), 1)
+	fc.Advance(100 * time.Millisecond)
+	expectValues(t, controlValues(md, signals, 7, 1), 1)
+	fc.Advance(100 * time.Millisecond)
+	expectValues(t, controlValues(md, signals, 7, 1), 1)
+}
@@ -0,0 +1,87 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/config"
+	"midi_manipulator/pkg/model"
+	"time"
+
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+)
+
+// Representation of chord entity
+type chordContext struct {
+	name   string
+	keys   []uint8
+	window time.Duration
+	active bool
+}
+
+// Function applies configuration of chords to MIDI-device entity
+func (md *MidiDevice) applyChords(chordsList []config.Chord) {
+	md.chords = make([]*chordContext, 0, len(chordsList))
+	for _, chord := range chordsList {
+		keys := make([]uint8, 0, len(chord.Keys))
+		for _, key := range chord.Keys {
+			keys = append(keys, uint8(key))
+		}
+		md.chords = append(md.chords, &chordContext{
+			name:   chord.Name,
+			keys:   keys,
+			window: time.Duration(chord.Window) * time.Millisecond,
+		})
+	}
+}
+
+// Function compares held keys with configured chords and returns signals of chord state changes
+func (md *MidiDevice) detectChords() []core.Signal {
+	if len(md.chords) == 0 {
+		return nil
+	}
+
+	pressedAt := make(map[uint8]time.Time)
+	for id, kctx := range md.clickBuffer {
+		if id.kind != NoteKind {
+			continue
+		}
+		switch kctx.status.(type) {
+		case model.NotePushed, model.NoteHold:
+			pressedAt[id.key] = kctx.usedAt
+		}
+	}
+
+	var signalSequence []core.Signal
+	for _, chord := range md.chords {
+		held, first, last := true, time.Time{}, time.Time{}
+		for _, key := range chord.keys {
+			usedAt, ok := pressedAt[key]
+			if !ok {
+				held = false
+				break
+			}
+			if first.IsZero() || usedAt.Before(first) {
+				first = usedAt
+			}
+			if usedAt.After(last) {
+				last = usedAt
+			}
+		}
+
+		switch {
+		case !chord.active && held && (chord.window == 0 || last.Sub(first) <= chord.window):
+			chord.active = true
+			signalSequence = append(signalSequence, model.ChordPressed{
+				Device:    md.name,
+				Namespace: md.namespace,
+				ChordName: chord.name,
+			})
+		case chord.active && !held:
+			chord.active = false
+			signalSequence = append(signalSequence, model.ChordReleased{
+				Device:    md.name,
+				Namespace: md.namespace,
+				ChordName: chord.name,
+			})
+		}
+	}
+	return signalSequence
+}
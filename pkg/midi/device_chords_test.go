@@ -0,0 +1,35 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/config"
+	"midi_manipulator/pkg/model"
+	"testing"
+	"time"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// Function checks that chord is pressed only when all keys are held within window
+func TestChordDetection(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.Chords = []config.Chord{{Name: "panic", Keys: []int{36, 37}, Window: 200}}
+	md, fc, signals := newTestDevice(t, conf)
+
+	md.processMidiMessage(midi.NoteOn(0, 36, 100), 0)
+	fc.Advance(100 * time.Millisecond)
+	md.processMidiMessage(midi.NoteOn(0, 37, 100), 0)
+	received := drainSignals(signals)
+	expectSignalCodes(t, received, "NotePushed", "NotePushed", "ChordPressed")
+	if chord := received[2].(model.ChordPressed); chord.ChordName != "panic" || chord.Namespace != "default" {
+		t.Fatalf("unexpected chord signal %+v", chord)
+	}
+
+	md.processMidiMessage(midi.NoteOff(0, 37), 0)
+	md.processMidiMessage(midi.NoteOff(0, 36), 0)
+	expectSignalCodes(t, drainSignals(signals), "NoteReleased", "ChordReleased", "NoteReleased")
+
+	md.processMidiMessage(midi.NoteOn(0, 36, 100), 0)
+	fc.Advance(300 * time.Millisecond)
+	md.processMidiMessage(midi.NoteOn(0, 37, 100), 0)
+	expectSignalCodes(t, drainSignals(signals), "NotePushed", "NotePushed")
+}
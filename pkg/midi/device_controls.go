@@ -1,29 +1,215 @@
 package midi
 
+import (
+	"midi_manipulator/pkg/config"
+	"midi_manipulator/pkg/model"
+	"sort"
+	"time"
+
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+)
+
 // Representation of control entity
 type Control struct {
 	Key              int
 	Rotate           bool
+	Passthrough      bool
 	ValueRange       [2]int
 	InitialValue     int
+	DefaultValue     int
 	IncrementTrigger int
 	DecrementTrigger int
+	EncoderMode      string
+	Pickup           bool
+	PickedUp         bool
+	LastPosition     int
+	Step             int
+	Acceleration     []config.AccelerationPoint
+	Feedback         config.ControlFeedback
+	UsedAt           time.Time
+}
+
+// Function decodes signed relative change from velocity according to encoder mode of control
+func (c *Control) decodeDelta(velocity int) int {
+	switch c.EncoderMode {
+	case config.EncoderTwosComplement:
+		if velocity >= 64 {
+			return velocity - 128
+		}
+		return velocity
+	case config.EncoderSignedBit:
+		if velocity&0x40 != 0 {
+			return -(velocity & 0x3F)
+		}
+		return velocity & 0x3F
+	case config.EncoderBinaryOffset:
+		return velocity - 64
+	default:
+		if velocity == c.IncrementTrigger {
+			return 1
+		} else if velocity == c.DecrementTrigger {
+			return -1
+		}
+		return 0
+	}
+}
+
+// Function sorts acceleration curve of control by interval
+func (c *Control) sortAcceleration() {
+	sort.Slice(c.Acceleration, func(i, j int) bool {
+		return c.Acceleration[i].Interval < c.Acceleration[j].Interval
+	})
+}
+
+// Function returns step multiplier according to time passed since previous change of control
+func (c *Control) accelerationMultiplier(now time.Time) int {
+	if c.UsedAt.IsZero() {
+		return 1
+	}
+	elapsed := now.Sub(c.UsedAt)
+	for _, point := range c.Acceleration {
+		if elapsed <= time.Duration(point.Interval)*time.Millisecond {
+			return point.Multiplier
+		}
+	}
+	return 1
+}
+
+// Function applies delta to accumulated value wrapping it around value range
+func (c *Control) wrapValue(delta int) int {
+	span := c.ValueRange[1] - c.ValueRange[0] + 1
+	offset := (c.InitialValue - c.ValueRange[0] + delta) % span
+	if offset < 0 {
+		offset += span
+	}
+	return c.ValueRange[0] + offset
+}
+
+// Function checks if physical position of control moved across target value
+func (c *Control) crossed(position int, target int) bool {
+	if c.LastPosition < 0 {
+		return position == target
+	}
+	return min(c.LastPosition, position) <= target && target <= max(c.LastPosition, position)
+}
+
+// Function handles behaviour of absolute control and returns modified value with applied delta
+func (md *MidiDevice) handleAbsoluteControl(controlKey int, control *Control, position int) (int, int, bool) {
+	value := max(control.ValueRange[0], min(position, control.ValueRange[1]))
+	if control.Pickup && !control.PickedUp {
+		if !control.crossed(value, control.InitialValue) {
+			control.LastPosition = value
+			return control.InitialValue, 0, false // value before takeover banned
+		}
+		control.PickedUp = true
+	}
+	control.LastPosition = value
+
+	delta := value - control.InitialValue
+	if delta == 0 {
+		return control.InitialValue, 0, false // unmodified value banned
+	}
+	control.InitialValue = value
+	md.storeControlValue(controlKey, value)
+	return value, delta, true
 }
 
-// Function handles behaviour of control by id and velocity and returns modified values
-func (md *MidiDevice) handleControls(controlKey int, controlVelocity int) (int, bool) {
+// Function handles behaviour of control by id and velocity and returns modified value with applied delta
+func (md *MidiDevice) handleControls(controlKey int, controlVelocity int) (int, int, bool) {
 	control, ok := md.controls[controlKey]
-	if ok && !control.Rotate {
-		if control.IncrementTrigger == controlVelocity && control.InitialValue < control.ValueRange[1]{
-			control.InitialValue++
-			return control.InitialValue, true
-		} else if control.DecrementTrigger == controlVelocity && control.InitialValue > control.ValueRange[0] {
-			control.InitialValue--
-			return control.InitialValue, true
+	if !ok || control.Passthrough {
+		return controlVelocity, 0, true // unfiltered value accepted
+	}
+	if control.EncoderMode == config.EncoderAbsolute {
+		return md.handleAbsoluteControl(controlKey, control, controlVelocity)
+	}
+
+	now := md.clock.Now()
+	multiplier := control.accelerationMultiplier(now)
+	control.UsedAt = now
+
+	delta := control.decodeDelta(controlVelocity) * control.Step * multiplier
+	if delta == 0 {
+		return control.InitialValue, 0, false // unmodified value banned
+	}
+
+	var value int
+	if control.Rotate {
+		value = control.wrapValue(delta)
+	} else {
+		value = max(control.ValueRange[0], min(control.InitialValue+delta, control.ValueRange[1]))
+		delta = value - control.InitialValue
+		if delta == 0 {
+			return control.InitialValue, 0, false // value at the edge of range banned
+		}
+	}
+	control.InitialValue = value
+	md.storeControlValue(controlKey, value)
+	return value, delta, true
+}
+
+// Function saves accumulated value of control to bank of active namespace and state store and shows it on feedback keys
+func (md *MidiDevice) storeControlValue(key int, value int) {
+	bank, ok := md.controlBanks[md.namespace]
+	if !ok {
+		bank = make(map[int]int)
+		md.controlBanks[md.namespace] = bank
+	}
+	bank[key] = value
+	if md.stateStore != nil {
+		md.stateStore.Save(md.name, md.namespace, key, value)
+	}
+	if control, ok := md.controls[key]; ok {
+		md.showControlFeedback(control)
+	}
+}
+
+// Function restores accumulated values of controls from bank of given namespace
+func (md *MidiDevice) loadControlBank(namespace string) {
+	bank, ok := md.controlBanks[namespace]
+	if !ok {
+		bank = make(map[int]int)
+		if md.stateStore != nil {
+			for key, value := range md.stateStore.Load(md.name, namespace) {
+				bank[key] = value
+			}
+		}
+		md.controlBanks[namespace] = bank
+	}
+
+	for key, control := range md.controls {
+		if control.Passthrough {
+			continue
+		}
+		control.PickedUp = false
+		if value, ok := bank[key]; ok {
+			control.InitialValue = max(control.ValueRange[0], min(value, control.ValueRange[1]))
 		} else {
-			return control.InitialValue, false // unmodified value banned 
+			control.InitialValue = control.DefaultValue
 		}
-	} else {
-		return controlVelocity, true // unfiltered value accepted
 	}
+	md.showAllControlFeedback()
+}
+
+// Function returns signals reporting accumulated values of all controls ordered by key
+func (md *MidiDevice) controlValueSignals() []core.Signal {
+	keys := make([]int, 0, len(md.controls))
+	for key, control := range md.controls {
+		if !control.Passthrough {
+			keys = append(keys, key)
+		}
+	}
+	sort.Ints(keys)
+
+	signalSequence := make([]core.Signal, 0, len(keys))
+	for _, key := range keys {
+		signalSequence = append(signalSequence, model.ControlValueReported{
+			Device:    md.name,
+			Namespace: md.namespace,
+			KeyCode:   key,
+			KeyName:   md.keyName(ControlKind, key),
+			Value:     md.controls[key].InitialValue,
+		})
+	}
+	return signalSequence
 }
@@ -0,0 +1,292 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/backlight"
+	"midi_manipulator/pkg/config"
+	"midi_manipulator/pkg/model"
+	"testing"
+	"time"
+
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+	"gitlab.com/gomidi/midi/v2"
+	"go.uber.org/zap"
+)
+
+// Function checks decoding of relative values for every encoder mode
+func TestControlDecodeDelta(t *testing.T) {
+	cases := []struct {
+		mode     string
+		velocity int
+		delta    int
+	}{
+		{config.EncoderTwosComplement, 1, 1},
+		{config.EncoderTwosComplement, 5, 5},
+		{config.EncoderTwosComplement, 127, -1},
+		{config.EncoderTwosComplement, 123, -5},
+		{config.EncoderSignedBit, 3, 3},
+		{config.EncoderSignedBit, 65, -1},
+		{config.EncoderSignedBit, 67, -3},
+		{config.EncoderBinaryOffset, 64, 0},
+		{config.EncoderBinaryOffset, 66, 2},
+		{config.EncoderBinaryOffset, 61, -3},
+		{config.EncoderTriggers, 127, 1},
+		{config.EncoderTriggers, 1, -1},
+		{"", 127, 1},
+		{"", 50, 0},
+	}
+	for _, c := range cases {
+		control := Control{EncoderMode: c.mode, IncrementTrigger: 127, DecrementTrigger: 1}
+		if delta := control.decodeDelta(c.velocity); delta != c.delta {
+			t.Fatalf("mode {%s}, velocity %d: expected delta %d, got %d", c.mode, c.velocity, c.delta, delta)
+		}
+	}
+}
+
+// Function checks that relative changes are applied with step and clamped to value range
+func TestAccumulateControlWithStep(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.Controls = []config.Controls{{
+		Keys:         []int{16},
+		ValueRange:   [2]int{0, 20},
+		InitialValue: 10,
+		EncoderMode:  config.EncoderTwosComplement,
+		Step:         2,
+	}}
+	md, _, signals := newTestDevice(t, conf)
+
+	md.processMidiMessage(midi.ControlChange(0, 16, 3), 0)
+	md.processMidiMessage(midi.ControlChange(0, 16, 127), 0)
+	md.processMidiMessage(midi.ControlChange(0, 16, 10), 0)
+	md.processMidiMessage(midi.ControlChange(0, 16, 1), 0)
+
+	received := drainSignals(signals)
+	expectSignalCodes(t, received, "ControlPushed", "ControlPushed", "ControlPushed")
+	expected := [][2]int{{16, 6}, {14, -2}, {20, 6}}
+	for idx, values := range expected {
+		control := received[idx].(model.ControlPushed)
+		if control.Value != values[0] || control.Delta != values[1] {
+			t.Fatalf("signal #%d: expected value %d and delta %d, got %+v", idx, values[0], values[1], control)
+		}
+	}
+}
+
+// Function checks that fast successive encoder events are multiplied by acceleration curve
+func TestAccumulateControlAcceleration(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.Controls = []config.Controls{{
+		Keys:        []int{16},
+		ValueRange:  [2]int{0, 127},
+		EncoderMode: config.EncoderBinaryOffset,
+		Acceleration: []config.AccelerationPoint{
+			{Interval: 100, Multiplier: 2},
+			{Interval: 20, Multiplier: 8},
+		},
+	}}
+	md, fc, signals := newTestDevice(t, conf)
+
+	md.processMidiMessage(midi.ControlChange(0, 16, 65), 0)
+	fc.Advance(500 * time.Millisecond)
+	md.processMidiMessage(midi.ControlChange(0, 16, 65), 0)
+	fc.Advance(50 * time.Millisecond)
+	md.processMidiMessage(midi.ControlChange(0, 16, 65), 0)
+	fc.Advance(10 * time.Millisecond)
+	md.processMidiMessage(midi.ControlChange(0, 16, 65), 0)
+
+	received := drainSignals(signals)
+	expectSignalCodes(t, received, "ControlPushed", "ControlPushed", "ControlPushed", "ControlPushed")
+	for idx, delta := range []int{1, 1, 2, 8} {
+		if control := received[idx].(model.ControlPushed); control.Delta != delta {
+			t.Fatalf("signal #%d: expected delta %d, got %+v", idx, delta, control)
+		}
+	}
+}
+
+// Function checks that rotate mode wraps accumulated value and passthrough mode keeps raw value
+func TestAccumulateControlRotateAndPassthrough(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.Controls = []config.Controls{
+		{Keys: []int{16}, Rotate: true, ValueRange: [2]int{0, 3}, InitialValue: 3, EncoderMode: config.EncoderTwosComplement},
+		{Keys: []int{17}, Passthrough: true, ValueRange: [2]int{0, 3}},
+	}
+	md, _, signals := newTestDevice(t, conf)
+
+	md.processMidiMessage(midi.ControlChange(0, 16, 1), 0)
+	md.processMidiMessage(midi.ControlChange(0, 16, 127), 0)
+	md.processMidiMessage(midi.ControlChange(0, 16, 6), 0)
+	md.processMidiMessage(midi.ControlChange(0, 17, 100), 0)
+
+	received := drainSignals(signals)
+	expectSignalCodes(t, received, "ControlPushed", "ControlPushed", "ControlPushed", "ControlPushed")
+	for idx, values := range [][2]int{{0, 1}, {3, -1}, {1, 6}, {100, 0}} {
+		control := received[idx].(model.ControlPushed)
+		if control.Value != values[0] || control.Delta != values[1] {
+			t.Fatalf("signal #%d: expected value %d and delta %d, got %+v", idx, values[0], values[1], control)
+		}
+	}
+}
+
+// Representation of in-memory state store used in tests
+type memoryStateStore map[string]map[int]int
+
+// Function returns saved values of device namespace
+func (s memoryStateStore) Load(device string, namespace string) map[int]int {
+	return s[device+"/"+namespace]
+}
+
+// Function saves value of device namespace control
+func (s memoryStateStore) Save(device string, namespace string, key int, value int) {
+	if _, ok := s[device+"/"+namespace]; !ok {
+		s[device+"/"+namespace] = make(map[int]int)
+	}
+	s[device+"/"+namespace][key] = value
+}
+
+// Function checks that accumulated values are saved to store and restored after controls reset
+func TestAccumulateControlPersistence(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.Controls = []config.Controls{{Keys: []int{16, 17}, ValueRange: [2]int{0, 10}, EncoderMode: config.EncoderBinaryOffset}}
+	md, _, signals := newTestDevice(t, conf)
+	store := memoryStateStore{"test/default": {17: 50}}
+	md.SetStateStore(store)
+
+	md.processMidiMessage(midi.ControlChange(0, 16, 67), 0)
+	expectSignalCodes(t, drainSignals(signals), "ControlPushed")
+	if store["test/default"][16] != 3 {
+		t.Fatalf("expected saved value 3, got %v", store["test/default"])
+	}
+
+	// VALUES ARE RESTORED BEFORE DEVICE IS CONNECTED
+	md, _, _ = newTestDevice(t, conf)
+	md.SetStateStore(store)
+	if value := md.controls[16].InitialValue; value != 3 {
+		t.Fatalf("expected restored value 3, got %d", value)
+	}
+	if value := md.controls[17].InitialValue; value != 10 {
+		t.Fatalf("expected restored value clamped to 10, got %d", value)
+	}
+	reported, err := md.executeCommand(model.GetControlValueCommand{KeyCode: 16}, nil)
+	if err != nil || len(reported) != 1 || reported[0].(model.ControlValueReported).Value != 3 {
+		t.Fatalf("expected persisted value 3 reported before connection, got %+v (%v)", reported, err)
+	}
+}
+
+// Function checks that every namespace keeps its own accumulated values
+func TestAccumulateControlBanks(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.Controls = []config.Controls{{Keys: []int{16, 17}, ValueRange: [2]int{0, 127}, InitialValue: 5, EncoderMode: config.EncoderBinaryOffset}}
+	md, _, signals := newTestDevice(t, conf)
+	backlightConfig := &backlight.DeviceBacklightConfig{}
+
+	md.processMidiMessage(midi.ControlChange(0, 16, 74), 0)
+	md.ExecuteCommand(model.SetActiveNamespaceCommand{Namespace: "mixer"}, backlightConfig)
+	received := drainSignals(signals)
+	expectSignalCodes(t, received, "ControlPushed", "NamespaceChanged", "ControlValueReported", "ControlValueReported")
+	if restored := received[2].(model.ControlValueReported); restored.KeyCode != 16 || restored.Value != 5 || restored.Namespace != "mixer" {
+		t.Fatalf("unexpected restored value %+v", restored)
+	}
+
+	md.processMidiMessage(midi.ControlChange(0, 16, 65), 0)
+	md.ExecuteCommand(model.SetActiveNamespaceCommand{Namespace: "default"}, backlightConfig)
+	received = drainSignals(signals)
+	expectSignalCodes(t, received, "ControlPushed", "NamespaceChanged", "ControlValueReported", "ControlValueReported")
+	if restored := received[2].(model.ControlValueReported); restored.KeyCode != 16 || restored.Value != 15 {
+		t.Fatalf("unexpected restored value %+v", restored)
+	}
+
+	md.ExecuteCommand(model.SetActiveNamespaceCommand{Namespace: "mixer"}, backlightConfig)
+	received = drainSignals(signals)
+	if restored := received[1].(model.ControlValueReported); restored.KeyCode != 16 || restored.Value != 6 {
+		t.Fatalf("unexpected restored value %+v", restored)
+	}
+}
+
+// Function checks setting and querying accumulated value of control by commands
+func TestSetAndGetControlValue(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.Controls = []config.Controls{{Keys: []int{16}, ValueRange: [2]int{0, 100}, EncoderMode: config.EncoderBinaryOffset}}
+	md, _, signals := newTestDevice(t, conf)
+	backlightConfig := &backlight.DeviceBacklightConfig{}
+
+	if err := md.ExecuteCommand(model.SetControlValueCommand{KeyCode: 16, Value: 500}, backlightConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := md.ExecuteCommand(model.GetControlValueCommand{KeyCode: 16}, backlightConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	received := drainSignals(signals)
+	expectSignalCodes(t, received, "ControlValueReported")
+	if reported := received[0].(model.ControlValueReported); reported.Value != 100 {
+		t.Fatalf("expected value clamped to 100, got %+v", reported)
+	}
+
+	md.processMidiMessage(midi.ControlChange(0, 16, 63), 0)
+	received = drainSignals(signals)
+	if control := received[0].(model.ControlPushed); control.Value != 99 {
+		t.Fatalf("expected encoder to continue from set value, got %+v", control)
+	}
+
+	if err := md.ExecuteCommand(model.GetControlValueCommand{KeyCode: 17}, backlightConfig); err == nil {
+		t.Fatalf("expected error for unknown control")
+	}
+}
+
+// Function checks that absolute control with pickup ignores values until stored value is crossed
+func TestAbsoluteControlPickup(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.Controls = []config.Controls{{Keys: []int{7}, ValueRange: [2]int{0, 127}, InitialValue: 64, EncoderMode: config.EncoderAbsolute, Pickup: true}}
+	md, _, signals := newTestDevice(t, conf)
+	backlightConfig := &backlight.DeviceBacklightConfig{}
+
+	for _, position := range []uint8{10, 30, 60, 70, 75} {
+		md.processMidiMessage(midi.ControlChange(0, 7, position), 0)
+	}
+	received := drainSignals(signals)
+	expectSignalCodes(t, received, "ControlPushed", "ControlPushed")
+	if control := received[0].(model.ControlPushed); control.Value != 70 || control.Delta != 6 {
+		t.Fatalf("unexpected value after takeover %+v", control)
+	}
+
+	md.ExecuteCommand(model.SetControlValueCommand{KeyCode: 7, Value: 20}, backlightConfig)
+	md.processMidiMessage(midi.ControlChange(0, 7, 80), 0)
+	md.processMidiMessage(midi.ControlChange(0, 7, 20), 0)
+	md.processMidiMessage(midi.ControlChange(0, 7, 15), 0)
+	received = drainSignals(signals)
+	expectSignalCodes(t, received, "ControlPushed")
+	if control := received[0].(model.ControlPushed); control.Value != 15 {
+		t.Fatalf("unexpected value after takeover %+v", control)
+	}
+}
+
+// Function checks that signals of namespace change are sent after device lock is released
+func TestSetActiveNamespaceSendsWithoutLock(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.Controls = []config.Controls{{Keys: []int{16}, ValueRange: [2]int{0, 127}, EncoderMode: config.EncoderBinaryOffset}}
+	signals := make(chan core.Signal)
+	md := NewDevice(conf, signals, zap.NewNop(), core.NewCheckManager())
+
+	go md.ExecuteCommand(model.SetActiveNamespaceCommand{Namespace: "mixer"}, &backlight.DeviceBacklightConfig{})
+	if signal := <-signals; signal.Code() != "NamespaceChanged" {
+		t.Fatalf("expected NamespaceChanged, got %v", signal)
+	}
+	if !md.mutex.TryLock() {
+		t.Fatalf("device is locked while waiting for signal consumer")
+	}
+	md.mutex.Unlock()
+	<-signals
+}
+
+// Function checks that reply to control value query is returned from locked section instead of being sent there
+func TestGetControlValueSendsWithoutLock(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.Controls = []config.Controls{{Keys: []int{16}, ValueRange: [2]int{0, 127}, EncoderMode: config.EncoderBinaryOffset}}
+	md, _, signals := newTestDevice(t, conf)
+
+	md.mutex.Lock()
+	signalSequence, err := md.executeCommand(model.GetControlValueCommand{KeyCode: 16}, &backlight.DeviceBacklightConfig{})
+	md.mutex.Unlock()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectSignalCodes(t, drainSignals(signals))
+	expectSignalCodes(t, signalSequence, "ControlValueReported")
+}
@@ -0,0 +1,79 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/config"
+	"midi_manipulator/pkg/model"
+	"slices"
+
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+)
+
+// Representation of exclusive selection context of named group of keys
+type keyGroup struct {
+	name     string
+	keys     []int
+	onColor  string
+	offColor string
+	selected int
+}
+
+// Function applies configuration of key groups to MIDI-device entity
+func (md *MidiDevice) applyKeyGroups(groups []config.KeyGroup) {
+	md.keyGroups = make([]*keyGroup, 0, len(groups))
+	for _, group := range groups {
+		md.keyGroups = append(md.keyGroups, &keyGroup{
+			name:     group.Name,
+			keys:     append([]int(nil), group.Keys...),
+			onColor:  group.OnColor,
+			offColor: group.OffColor,
+			selected: -1,
+		})
+	}
+}
+
+// Function selects pushed key in its group and returns signal if selection changed
+func (md *MidiDevice) selectGroupKey(kctx *KeyContext) core.Signal {
+	if kctx.id.kind != NoteKind {
+		return nil
+	}
+	key := int(kctx.id.key)
+	for _, group := range md.keyGroups {
+		if !slices.Contains(group.keys, key) {
+			continue
+		}
+		if group.selected == key {
+			return nil // selected key pressed again
+		}
+		group.selected = key
+		md.showKeyGroup(group)
+		return model.GroupSelectionChanged{
+			Device:    md.name,
+			Namespace: md.namespace,
+			GroupName: group.name,
+			KeyCode:   key,
+			KeyName:   md.keyName(NoteKind, key),
+		}
+	}
+	return nil
+}
+
+// Function lights selected key of group and darkens others
+func (md *MidiDevice) showKeyGroup(group *keyGroup) {
+	if md.backlightConfig == nil {
+		return
+	}
+	for _, key := range group.keys {
+		if key == group.selected {
+			md.turnLightOn(model.TurnLightOnCommand{KeyCode: key, ColorName: group.onColor}, md.backlightConfig)
+		} else {
+			md.turnLightOff(model.TurnLightOffCommand{KeyCode: key, ColorName: group.offColor}, md.backlightConfig)
+		}
+	}
+}
+
+// Function shows selection of all key groups
+func (md *MidiDevice) showAllKeyGroups() {
+	for _, group := range md.keyGroups {
+		md.showKeyGroup(group)
+	}
+}
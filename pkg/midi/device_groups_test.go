@@ -0,0 +1,35 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/config"
+	"midi_manipulator/pkg/model"
+	"testing"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// Function checks that pressing key of group selects it and deselects others
+func TestKeyGroups(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.KeyGroups = []config.KeyGroup{{Name: "scene", Keys: []int{0, 1, 2}, OnColor: "blue"}}
+	md, _, signals := newTestDevice(t, conf)
+	out := attachTestBacklight(t, md)
+
+	for _, key := range []uint8{1, 1, 2} {
+		md.processMidiMessage(midi.NoteOn(0, key, 100), 0)
+		md.processMidiMessage(midi.NoteOff(0, key), 0)
+	}
+
+	var selections []model.GroupSelectionChanged
+	for _, signal := range drainSignals(signals) {
+		if selection, ok := signal.(model.GroupSelectionChanged); ok {
+			selections = append(selections, selection)
+		}
+	}
+	if len(selections) != 2 || selections[0].KeyCode != 1 || selections[1].KeyCode != 2 || selections[1].GroupName != "scene" {
+		t.Fatalf("expected selection of keys 1 and 2 in group scene, got %+v", selections)
+	}
+	expectSent(t, out.drain(),
+		[]byte{0x80, 0, 0x00}, []byte{0x90, 1, 0x03}, []byte{0x80, 2, 0x00},
+		[]byte{0x80, 0, 0x00}, []byte{0x80, 1, 0x00}, []byte{0x90, 2, 0x03})
+}
@@ -8,15 +8,18 @@ import (
 	"midi_manipulator/pkg/backlight"
 	"midi_manipulator/pkg/config"
 	"midi_manipulator/pkg/model"
+	"midi_manipulator/pkg/state"
 	"sync"
 )
 
 // Representation of device manager entity
 type DeviceManager struct {
 	devices         map[string]*MidiDevice
+	sequencers      map[string]*sequencer
 	mutex           sync.Mutex
 	signals         chan core.Signal
 	backlightConfig *backlight.DeviceBacklightConfig
+	stateStore      state.Store
 	logger          *zap.Logger
 	checkManager    core.CheckRegistry
 }
@@ -26,6 +29,11 @@ func (dm *DeviceManager) SetBacklightConfig(cfg *backlight.DeviceBacklightConfig
 	dm.backlightConfig = cfg
 }
 
+// Function sets storage used by devices to persist accumulated values of controls
+func (dm *DeviceManager) SetStateStore(store state.Store) {
+	dm.stateStore = store
+}
+
 // Function returns object representing MIDI-device from current device list
 func (dm *DeviceManager) getDevice(alias string) (*MidiDevice, bool) {
 	dm.mutex.Lock()
@@ -68,6 +76,16 @@ func (dm *DeviceManager) ExecuteOnDevice(alias string, cmd model.MidiCommand) er
 	return nil
 }
 
+// Function returns signal sequencer of device by its alias, kept when device is recreated
+func (dm *DeviceManager) deviceSequencer(alias string) *sequencer {
+	deviceSequencer, ok := dm.sequencers[alias]
+	if !ok {
+		deviceSequencer = newSequencer()
+		dm.sequencers[alias] = deviceSequencer
+	}
+	return deviceSequencer
+}
+
 // Function handles device list update process
 func (dm *DeviceManager) UpdateDevices(midiConfig []config.DeviceConfig) {
 	dm.mutex.Lock()
@@ -83,6 +101,8 @@ func (dm *DeviceManager) UpdateDevices(midiConfig []config.DeviceConfig) {
 	dm.devices = make(map[string]*MidiDevice)
 	for _, deviceConfig := range midiConfig {
 		newDevice := NewDevice(deviceConfig, dm.signals, dm.logger, dm.checkManager)
+		newDevice.sequencer = dm.deviceSequencer(newDevice.name)
+		newDevice.SetStateStore(dm.stateStore)
 		dm.devices[newDevice.name] = newDevice
 		go newDevice.RunDevice(dm.backlightConfig)
 	}
@@ -100,6 +120,7 @@ func NewDeviceManager(
 ) *DeviceManager {
 	dm := DeviceManager{logger: logger, checkManager: checkManager}
 	dm.devices = make(map[string]*MidiDevice)
+	dm.sequencers = make(map[string]*sequencer)
 	dm.signals = make(chan core.Signal)
 
 	logger.Info("Created device manager")
@@ -0,0 +1,55 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/model"
+	"time"
+
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+)
+
+// Representation of tap sequence context of single key
+type tapContext struct {
+	count      int
+	releasedAt time.Time
+}
+
+// Function registers short click of key and returns tap signal if click continues tap sequence
+func (md *MidiDevice) registerTap(kctx *KeyContext) core.Signal {
+	if md.tapWindow == 0 {
+		return nil
+	}
+
+	tctx, ok := md.taps[kctx.id]
+	if ok && kctx.usedAt.Sub(tctx.releasedAt) <= md.tapWindow {
+		tctx.count++
+	} else {
+		tctx.count = 1
+	}
+	tctx.releasedAt = md.clock.Now()
+	md.taps[kctx.id] = tctx
+
+	switch {
+	case tctx.count == 2:
+		return model.NoteDoubleTapped{
+			Device:    md.name,
+			Namespace: md.namespace,
+			Channel:   int(kctx.id.channel),
+			Kind:      kctx.id.kind.String(),
+			KeyCode:   int(kctx.id.key),
+			KeyName:   md.keyName(kctx.id.kind, int(kctx.id.key)),
+			Velocity:  int(kctx.velocity),
+		}
+	case tctx.count > 2:
+		return model.NoteMultiTapped{
+			Device:    md.name,
+			Namespace: md.namespace,
+			Channel:   int(kctx.id.channel),
+			Kind:      kctx.id.kind.String(),
+			KeyCode:   int(kctx.id.key),
+			KeyName:   md.keyName(kctx.id.kind, int(kctx.id.key)),
+			Velocity:  int(kctx.velocity),
+			TapCount:  tctx.count,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/model"
+	"testing"
+	"time"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// Function presses and releases key with given press duration
+func tap(md *MidiDevice, fc *fakeClock, key uint8, pressed time.Duration) {
+	md.processMidiMessage(midi.NoteOn(0, key, 100), 0)
+	fc.Advance(pressed)
+	md.processMidiMessage(midi.NoteOff(0, key), 0)
+}
+
+// Function checks that repeated short clicks within tap window produce tap signals
+func TestMultiTap(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.TapWindow = 300
+	md, fc, signals := newTestDevice(t, conf)
+
+	tap(md, fc, 60, 50*time.Millisecond)
+	expectSignalCodes(t, drainSignals(signals), "NotePushed", "NoteReleased")
+
+	fc.Advance(200 * time.Millisecond)
+	tap(md, fc, 60, 50*time.Millisecond)
+	expectSignalCodes(t, drainSignals(signals), "NotePushed", "NoteReleased", "NoteDoubleTapped")
+
+	fc.Advance(200 * time.Millisecond)
+	tap(md, fc, 60, 50*time.Millisecond)
+	received := drainSignals(signals)
+	expectSignalCodes(t, received, "NotePushed", "NoteReleased", "NoteMultiTapped")
+	if multi := received[2].(model.NoteMultiTapped); multi.TapCount != 3 {
+		t.Fatalf("expected tap count 3, got %d", multi.TapCount)
+	}
+
+	fc.Advance(400 * time.Millisecond)
+	tap(md, fc, 60, 50*time.Millisecond)
+	expectSignalCodes(t, drainSignals(signals), "NotePushed", "NoteReleased")
+}
+
+// Function checks that hold interrupts tap sequence and zero tap window disables taps
+func TestTapInterruptedByHold(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.TapWindow = 300
+	md, fc, signals := newTestDevice(t, conf)
+
+	tap(md, fc, 60, 50*time.Millisecond)
+	fc.Advance(100 * time.Millisecond)
+	tap(md, fc, 60, md.holdDelta)
+	fc.Advance(100 * time.Millisecond)
+	tap(md, fc, 60, 50*time.Millisecond)
+	expectSignalCodes(t, drainSignals(signals),
+		"NotePushed", "NoteReleased",
+		"NotePushed", "NoteHold", "NoteReleasedAfterHold",
+		"NotePushed", "NoteReleased",
+	)
+
+	md, fc, signals = newTestDevice(t, testDeviceConfig())
+	tap(md, fc, 60, 50*time.Millisecond)
+	tap(md, fc, 60, 50*time.Millisecond)
+	expectSignalCodes(t, drainSignals(signals), "NotePushed", "NoteReleased", "NotePushed", "NoteReleased")
+}
@@ -0,0 +1,69 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/config"
+	"midi_manipulator/pkg/model"
+	"sort"
+
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+)
+
+// Representation of on/off state of single toggle key
+type toggleKey struct {
+	onColor  string
+	offColor string
+	state    bool
+}
+
+// Function applies configuration of toggle keys to MIDI-device entity
+func (md *MidiDevice) applyToggleKeys(toggles []config.ToggleKeys) {
+	md.toggles = make(map[int]*toggleKey)
+	for _, toggle := range toggles {
+		for _, key := range toggle.Keys {
+			md.toggles[key] = &toggleKey{onColor: toggle.OnColor, offColor: toggle.OffColor}
+		}
+	}
+}
+
+// Function flips state of pushed toggle key and returns signal with its new state
+func (md *MidiDevice) toggle(kctx *KeyContext) core.Signal {
+	key := int(kctx.id.key)
+	toggle, ok := md.toggles[key]
+	if !ok || kctx.id.kind != NoteKind {
+		return nil
+	}
+	toggle.state = !toggle.state
+	md.showToggle(key, toggle)
+	return model.NoteToggled{
+		Device:    md.name,
+		Namespace: md.namespace,
+		Channel:   int(kctx.id.channel),
+		KeyCode:   key,
+		KeyName:   md.keyName(NoteKind, key),
+		State:     toggle.state,
+	}
+}
+
+// Function lights or darkens toggle key according to its state
+func (md *MidiDevice) showToggle(key int, toggle *toggleKey) {
+	if md.backlightConfig == nil {
+		return
+	}
+	if toggle.state {
+		md.turnLightOn(model.TurnLightOnCommand{KeyCode: key, ColorName: toggle.onColor}, md.backlightConfig)
+	} else {
+		md.turnLightOff(model.TurnLightOffCommand{KeyCode: key, ColorName: toggle.offColor}, md.backlightConfig)
+	}
+}
+
+// Function shows states of all toggle keys ordered by key
+func (md *MidiDevice) showAllToggles() {
+	keys := make([]int, 0, len(md.toggles))
+	for key := range md.toggles {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	for _, key := range keys {
+		md.showToggle(key, md.toggles[key])
+	}
+}
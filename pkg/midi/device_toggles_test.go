@@ -0,0 +1,34 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/config"
+	"midi_manipulator/pkg/model"
+	"testing"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// Function checks that each press of toggle key flips its state and light
+func TestToggleKeys(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.ToggleKeys = []config.ToggleKeys{{Keys: []int{4}, OnColor: "green"}}
+	md, _, signals := newTestDevice(t, conf)
+	out := attachTestBacklight(t, md)
+
+	for idx := 0; idx < 2; idx++ {
+		md.processMidiMessage(midi.NoteOn(0, 4, 100), 0)
+		md.processMidiMessage(midi.NoteOff(0, 4), 0)
+	}
+	md.processMidiMessage(midi.NoteOn(0, 5, 100), 0)
+
+	received := drainSignals(signals)
+	expectSignalCodes(t, received,
+		"NotePushed", "NoteToggled", "NoteReleased", "NotePushed", "NoteToggled", "NoteReleased", "NotePushed")
+	if toggled := received[1].(model.NoteToggled); !toggled.State || toggled.KeyCode != 4 {
+		t.Fatalf("expected key 4 toggled on, got %+v", toggled)
+	}
+	if toggled := received[4].(model.NoteToggled); toggled.State {
+		t.Fatalf("expected key 4 toggled off, got %+v", toggled)
+	}
+	expectSent(t, out.drain(), []byte{0x90, 4, 0x02}, []byte{0x80, 4, 0x00})
+}
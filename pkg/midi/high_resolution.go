@@ -0,0 +1,209 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/config"
+	"midi_manipulator/pkg/model"
+
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+)
+
+// Controller numbers used by NRPN/RPN parameter sequences
+const (
+	nrpnParameterLSB = 98
+	nrpnParameterMSB = 99
+	rpnParameterLSB  = 100
+	rpnParameterMSB  = 101
+	dataEntryMSB     = 6
+	dataEntryLSB     = 38
+)
+
+// Representation of high resolution parameter identifiers
+type parameterIdentifiers struct {
+	kind   string
+	number int
+}
+
+// Representation of controller identifiers on single channel
+type channelControlIdentifiers struct {
+	channel    uint8
+	controller uint8
+}
+
+// Representation of NRPN/RPN sequence state of single channel
+type parameterContext struct {
+	kind         string
+	parameterMSB uint8
+	parameterLSB uint8
+	dataMSB      uint8
+	selected     bool
+	pending      []controlChange
+}
+
+// Representation of single 7-bit control change
+type controlChange struct {
+	controller uint8
+	value      uint8
+}
+
+// Representation of reassembled high resolution value
+type highResolutionValue struct {
+	kind   string
+	number int
+	value  int
+}
+
+// Representation of decoder entity reassembling 14-bit values from pairs of 7-bit controls
+type highResolutionDecoder struct {
+	msbControls map[uint8]uint8
+	lsbControls map[uint8]uint8
+	parameters  map[parameterIdentifiers]struct{}
+	msbValues   map[channelControlIdentifiers]uint8
+	sequences   map[uint8]*parameterContext
+}
+
+// Function initializes high resolution decoder entity with values
+func newHighResolutionDecoder(controlsList []config.HighResolutionControl) *highResolutionDecoder {
+	d := highResolutionDecoder{
+		msbControls: make(map[uint8]uint8),
+		lsbControls: make(map[uint8]uint8),
+		parameters:  make(map[parameterIdentifiers]struct{}),
+		msbValues:   make(map[channelControlIdentifiers]uint8),
+		sequences:   make(map[uint8]*parameterContext),
+	}
+	for _, control := range controlsList {
+		switch control.Type {
+		case config.HighResolutionCC14:
+			d.msbControls[uint8(control.MSB)] = uint8(control.LSB)
+			d.lsbControls[uint8(control.LSB)] = uint8(control.MSB)
+		case config.HighResolutionNRPN, config.HighResolutionRPN:
+			d.parameters[parameterIdentifiers{control.Type, control.Parameter}] = struct{}{}
+		}
+	}
+	return &d
+}
+
+// Function consumes control change and returns reassembled value with controls to be handled as ordinary ones
+func (d *highResolutionDecoder) decode(channel, controller, value uint8) (*highResolutionValue, []controlChange) {
+	if _, ok := d.msbControls[controller]; ok {
+		// MSB ALONE IS VALID COARSE VALUE WITH IMPLIED LSB OF 0
+		d.msbValues[channelControlIdentifiers{channel, controller}] = value
+		return &highResolutionValue{kind: config.HighResolutionCC14, number: int(controller), value: int(value) << 7}, nil
+	}
+	if msb, ok := d.lsbControls[controller]; ok {
+		return &highResolutionValue{
+			kind:   config.HighResolutionCC14,
+			number: int(msb),
+			value:  int(d.msbValues[channelControlIdentifiers{channel, msb}])<<7 | int(value),
+		}, nil
+	}
+	current := []controlChange{{controller, value}}
+	if len(d.parameters) == 0 {
+		return nil, current
+	}
+
+	sequence, ok := d.sequences[channel]
+	if !ok {
+		sequence = &parameterContext{}
+		d.sequences[channel] = sequence
+	}
+	switch controller {
+	case nrpnParameterMSB:
+		return nil, d.selectParameter(sequence, config.HighResolutionNRPN, 7, controlChange{controller, value})
+	case nrpnParameterLSB:
+		return nil, d.selectParameter(sequence, config.HighResolutionNRPN, 0, controlChange{controller, value})
+	case rpnParameterMSB:
+		return nil, d.selectParameter(sequence, config.HighResolutionRPN, 7, controlChange{controller, value})
+	case rpnParameterLSB:
+		return nil, d.selectParameter(sequence, config.HighResolutionRPN, 0, controlChange{controller, value})
+	case dataEntryMSB, dataEntryLSB:
+		released := d.resolveSelection(sequence)
+		if !sequence.selected {
+			return nil, append(released, current...) // data entry without selected parameter is ordinary control
+		}
+		number := int(sequence.parameterMSB)<<7 | int(sequence.parameterLSB)
+		if controller == dataEntryMSB {
+			// DATA ENTRY MSB ALONE IS VALID COARSE VALUE WITH IMPLIED LSB OF 0
+			sequence.dataMSB = value
+			return &highResolutionValue{kind: sequence.kind, number: number, value: int(value) << 7}, released
+		}
+		return &highResolutionValue{
+			kind:   sequence.kind,
+			number: number,
+			value:  int(sequence.dataMSB)<<7 | int(value),
+		}, released
+	}
+	return nil, current
+}
+
+// Function tracks half of parameter number at shift and holds it until selected pair is known
+func (d *highResolutionDecoder) selectParameter(
+	sequence *parameterContext,
+	kind string,
+	shift int,
+	change controlChange,
+) []controlChange {
+	var released []controlChange
+	if sequence.kind != kind {
+		released = d.releaseSelection(sequence)
+	}
+	sequence.kind = kind
+	if shift == 7 {
+		sequence.parameterMSB = change.value
+	} else {
+		sequence.parameterLSB = change.value
+	}
+	sequence.selected = false
+	sequence.pending = append(sequence.pending, change)
+	if len(sequence.pending) < 2 && d.hasParameterPart(kind, shift, change.value) {
+		return released // wait for other half of pair
+	}
+	return append(released, d.resolveSelection(sequence)...)
+}
+
+// Function decides whether held parameter select controls form configured parameter and returns them if not
+func (d *highResolutionDecoder) resolveSelection(sequence *parameterContext) []controlChange {
+	if len(sequence.pending) == 0 {
+		return nil
+	}
+	number := int(sequence.parameterMSB)<<7 | int(sequence.parameterLSB)
+	if _, ok := d.parameters[parameterIdentifiers{sequence.kind, number}]; ok {
+		sequence.selected = true
+		sequence.pending = nil
+		return nil
+	}
+	return d.releaseSelection(sequence)
+}
+
+// Function returns held parameter select controls to be handled as ordinary ones
+func (d *highResolutionDecoder) releaseSelection(sequence *parameterContext) []controlChange {
+	released := sequence.pending
+	sequence.selected = false
+	sequence.pending = nil
+	return released
+}
+
+// Function checks if 7-bit part of parameter number at shift belongs to any configured parameter of kind
+func (d *highResolutionDecoder) hasParameterPart(kind string, shift int, value uint8) bool {
+	for parameter := range d.parameters {
+		if parameter.kind == kind && uint8(parameter.number>>shift&0x7F) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Function converts control change belonging to high resolution control to signal and returns controls not consumed
+func (md *MidiDevice) decodeHighResolution(channel, controller, value uint8) (core.Signal, []controlChange) {
+	decoded, passed := md.highResolution.decode(channel, controller, value)
+	if decoded == nil {
+		return nil, passed
+	}
+	return model.HighResolutionControlPushed{
+		Device:    md.name,
+		Namespace: md.namespace,
+		Channel:   int(channel),
+		Type:      decoded.kind,
+		KeyCode:   decoded.number,
+		Value:     decoded.value,
+	}, passed
+}
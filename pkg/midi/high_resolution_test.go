@@ -0,0 +1,126 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/config"
+	"midi_manipulator/pkg/model"
+	"testing"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// Function checks reassembling of 14-bit control change pairs and NRPN/RPN sequences
+func TestHighResolutionControls(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.HighResolution = []config.HighResolutionControl{
+		{Type: config.HighResolutionCC14, MSB: 7, LSB: 39},
+		{Type: config.HighResolutionNRPN, Parameter: 1234},
+		{Type: config.HighResolutionRPN, Parameter: 0},
+	}
+	md, fc, signals := newTestDevice(t, conf)
+	timestamp := fc.Now().UnixMilli()
+
+	md.processMidiMessage(midi.ControlChange(0, 7, 0x7F), 0)
+	md.processMidiMessage(midi.ControlChange(0, 39, 0x7F), 0)
+
+	md.processMidiMessage(midi.ControlChange(1, nrpnParameterMSB, 1234>>7), 0)
+	md.processMidiMessage(midi.ControlChange(1, nrpnParameterLSB, 1234&0x7F), 0)
+	md.processMidiMessage(midi.ControlChange(1, dataEntryMSB, 0x40), 0)
+	md.processMidiMessage(midi.ControlChange(1, dataEntryLSB, 0x00), 0)
+
+	md.processMidiMessage(midi.ControlChange(1, rpnParameterMSB, 0), 0)
+	md.processMidiMessage(midi.ControlChange(1, rpnParameterLSB, 0), 0)
+	md.processMidiMessage(midi.ControlChange(1, dataEntryMSB, 2), 0)
+	md.processMidiMessage(midi.ControlChange(1, dataEntryLSB, 0), 0)
+
+	md.processMidiMessage(midi.ControlChange(0, 16, 5), 0)
+
+	received := drainSignals(signals)
+	expectSignalCodes(t, received,
+		"HighResolutionControlPushed", "HighResolutionControlPushed", "HighResolutionControlPushed",
+		"HighResolutionControlPushed", "HighResolutionControlPushed", "HighResolutionControlPushed", "ControlPushed")
+	// MSB IS EMITTED WITH IMPLIED LSB OF 0 AND REFINED BY LSB
+	expected := []model.HighResolutionControlPushed{
+		{Device: "test", Namespace: "default", Channel: 0, Type: config.HighResolutionCC14, KeyCode: 7, Value: 16256,
+			Timestamp: timestamp, Sequence: 1},
+		{Device: "test", Namespace: "default", Channel: 0, Type: config.HighResolutionCC14, KeyCode: 7, Value: 16383,
+			Timestamp: timestamp, Sequence: 2},
+		{Device: "test", Namespace: "default", Channel: 1, Type: config.HighResolutionNRPN, KeyCode: 1234, Value: 8192,
+			Timestamp: timestamp, Sequence: 3},
+		{Device: "test", Namespace: "default", Channel: 1, Type: config.HighResolutionNRPN, KeyCode: 1234, Value: 8192,
+			Timestamp: timestamp, Sequence: 4},
+		{Device: "test", Namespace: "default", Channel: 1, Type: config.HighResolutionRPN, KeyCode: 0, Value: 256,
+			Timestamp: timestamp, Sequence: 5},
+		{Device: "test", Namespace: "default", Channel: 1, Type: config.HighResolutionRPN, KeyCode: 0, Value: 256,
+			Timestamp: timestamp, Sequence: 6},
+	}
+	for idx, signal := range expected {
+		if received[idx] != signal {
+			t.Fatalf("signal #%d: expected %+v, got %+v", idx, signal, received[idx])
+		}
+	}
+}
+
+// Function checks that data entry and parameter select controls pass through without selected configured parameter
+func TestHighResolutionPassthrough(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.HighResolution = []config.HighResolutionControl{{Type: config.HighResolutionNRPN, Parameter: 1234}}
+	md, _, signals := newTestDevice(t, conf)
+
+	md.processMidiMessage(midi.ControlChange(0, dataEntryMSB, 10), 0)
+	md.processMidiMessage(midi.ControlChange(0, dataEntryLSB, 20), 0)
+
+	md.processMidiMessage(midi.ControlChange(0, nrpnParameterMSB, 1), 0)
+	md.processMidiMessage(midi.ControlChange(0, nrpnParameterLSB, 2), 0)
+	md.processMidiMessage(midi.ControlChange(0, dataEntryMSB, 30), 0)
+
+	md.processMidiMessage(midi.ControlChange(1, nrpnParameterMSB, 1234>>7), 0)
+	md.processMidiMessage(midi.ControlChange(1, nrpnParameterLSB, 1234&0x7F), 0)
+	md.processMidiMessage(midi.ControlChange(0, dataEntryMSB, 40), 0)
+
+	received := drainSignals(signals)
+	expectSignalCodes(t, received,
+		"ControlPushed", "ControlPushed", "ControlPushed", "ControlPushed", "ControlPushed", "ControlPushed")
+	if pushed := received[0].(model.ControlPushed); pushed.KeyCode != dataEntryMSB || pushed.Value != 10 {
+		t.Fatalf("expected CC 6 to pass through as ControlPushed, got %+v", pushed)
+	}
+
+	// MSB OF CONFIGURED PARAMETER IS HELD AND PASSES THROUGH WITH LSB OF OTHER PARAMETER
+	md.processMidiMessage(midi.ControlChange(2, nrpnParameterMSB, 1234>>7), 0)
+	if received := drainSignals(signals); len(received) != 0 {
+		t.Fatalf("expected half of parameter select to be held, got %+v", received)
+	}
+	md.processMidiMessage(midi.ControlChange(2, nrpnParameterLSB, 3), 0)
+	md.processMidiMessage(midi.ControlChange(2, dataEntryMSB, 50), 0)
+
+	received = drainSignals(signals)
+	expectSignalCodes(t, received, "ControlPushed", "ControlPushed", "ControlPushed")
+	for idx, key := range []int{nrpnParameterLSB, nrpnParameterMSB, dataEntryMSB} {
+		if pushed := received[idx].(model.ControlPushed); pushed.KeyCode != key {
+			t.Fatalf("signal #%d: expected CC %d to pass through, got %+v", idx, key, pushed)
+		}
+	}
+}
+
+// Function checks that coarse MSB-only stream of high resolution controls is emitted with implied LSB of 0
+func TestHighResolutionMSBOnly(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.HighResolution = []config.HighResolutionControl{
+		{Type: config.HighResolutionCC14, MSB: 7, LSB: 39},
+		{Type: config.HighResolutionNRPN, Parameter: 1234},
+	}
+	md, _, signals := newTestDevice(t, conf)
+
+	md.processMidiMessage(midi.ControlChange(0, 7, 10), 0)
+	md.processMidiMessage(midi.ControlChange(0, 7, 11), 0)
+	md.processMidiMessage(midi.ControlChange(0, nrpnParameterMSB, 1234>>7), 0)
+	md.processMidiMessage(midi.ControlChange(0, nrpnParameterLSB, 1234&0x7F), 0)
+	md.processMidiMessage(midi.ControlChange(0, dataEntryMSB, 20), 0)
+
+	received := drainSignals(signals)
+	expectSignalCodes(t, received, "HighResolutionControlPushed", "HighResolutionControlPushed", "HighResolutionControlPushed")
+	for idx, value := range []int{10 << 7, 11 << 7, 20 << 7} {
+		if pushed := received[idx].(model.HighResolutionControlPushed); pushed.Value != value {
+			t.Fatalf("signal #%d: expected value %d, got %+v", idx, value, pushed)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/config"
+	"sort"
+	"time"
+)
+
+// Representation of named hold level entity for range of keys
+type holdLevel struct {
+	name     string
+	delta    time.Duration
+	allKeys  bool
+	keyRange [2]uint8
+}
+
+// Function applies configuration of hold levels to MIDI-device entity ordered by delta
+func (md *MidiDevice) applyHoldLevels(levelsList []config.HoldLevel) {
+	md.holdLevels = make([]holdLevel, 0, len(levelsList))
+	for _, level := range levelsList {
+		hl := holdLevel{
+			name:    level.Name,
+			delta:   time.Duration(level.Delta) * time.Millisecond,
+			allKeys: len(level.KeyRange) == 0,
+		}
+		if !hl.allKeys {
+			hl.keyRange = [2]uint8{uint8(level.KeyRange[0]), uint8(level.KeyRange[1])}
+		}
+		md.holdLevels = append(md.holdLevels, hl)
+	}
+	sort.SliceStable(md.holdLevels, func(i, j int) bool {
+		return md.holdLevels[i].delta < md.holdLevels[j].delta
+	})
+}
+
+// Function returns hold levels matching key or default level with hold delta if none matches
+func (md *MidiDevice) keyHoldLevels(id KeyIdentifiers) []holdLevel {
+	var levels []holdLevel
+	for _, level := range md.holdLevels {
+		if level.allKeys || (level.keyRange[0] <= id.key && id.key <= level.keyRange[1]) {
+			levels = append(levels, level)
+		}
+	}
+	if len(levels) == 0 {
+		return []holdLevel{{name: config.DefaultHoldLevel, delta: md.holdDelta, allKeys: true}}
+	}
+	return levels
+}
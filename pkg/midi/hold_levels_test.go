@@ -0,0 +1,64 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/config"
+	"midi_manipulator/pkg/model"
+	"testing"
+	"time"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// Function checks that held key reaches hold levels in order and release carries the last reached level
+func TestHoldLevels(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.HoldLevels = []config.HoldLevel{
+		{Name: "long_hold", Delta: 3000, KeyRange: []int{40, 40}},
+		{Name: config.DefaultHoldLevel, Delta: 1000},
+	}
+	md, fc, signals := newTestDevice(t, conf)
+
+	md.processMidiMessage(midi.NoteOn(0, 40, 100), 0)
+	fc.Advance(3500 * time.Millisecond)
+	md.processMidiMessage(midi.NoteOff(0, 40), 0)
+
+	md.processMidiMessage(midi.NoteOn(0, 41, 100), 0)
+	fc.Advance(3500 * time.Millisecond)
+	md.processMidiMessage(midi.NoteOff(0, 41), 0)
+
+	received := drainSignals(signals)
+	expectSignalCodes(t, received,
+		"NotePushed", "NoteHold", "NoteHold", "NoteReleasedAfterHold",
+		"NotePushed", "NoteHold", "NoteReleasedAfterHold")
+	levels := []string{
+		received[1].(model.NoteHold).Level,
+		received[2].(model.NoteHold).Level,
+		received[3].(model.NoteReleasedAfterHold).Level,
+		received[5].(model.NoteHold).Level,
+		received[6].(model.NoteReleasedAfterHold).Level,
+	}
+	expected := []string{"hold", "long_hold", "long_hold", "hold", "hold"}
+	for idx := range expected {
+		if levels[idx] != expected[idx] {
+			t.Fatalf("expected levels %v, got %v", expected, levels)
+		}
+	}
+}
+
+// Function checks that release before the next level keeps the reached level
+func TestHoldLevelsReleaseBetweenLevels(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.HoldLevels = []config.HoldLevel{{Name: "hold", Delta: 1000}, {Name: "long_hold", Delta: 3000}}
+	md, fc, signals := newTestDevice(t, conf)
+
+	md.processMidiMessage(midi.NoteOn(0, 40, 100), 0)
+	fc.Advance(2 * time.Second)
+	md.processMidiMessage(midi.NoteOff(0, 40), 0)
+	fc.Advance(2 * time.Second)
+
+	received := drainSignals(signals)
+	expectSignalCodes(t, received, "NotePushed", "NoteHold", "NoteReleasedAfterHold")
+	if released := received[2].(model.NoteReleasedAfterHold); released.Level != "hold" {
+		t.Fatalf("expected release after hold level, got %+v", released)
+	}
+}
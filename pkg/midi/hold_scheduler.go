@@ -0,0 +1,74 @@
+package midi
+
+import (
+	"sync"
+	"time"
+)
+
+// Representation of hold scheduler entity tracking one timer per pressed key
+type holdScheduler struct {
+	clock      clock
+	mutex      sync.Mutex
+	generation uint64
+	timers     map[KeyIdentifiers]scheduledTimer
+}
+
+// Representation of timer scheduled for key with generation distinguishing it from replaced ones
+type scheduledTimer struct {
+	generation uint64
+	timer      timer
+}
+
+// Function initializes hold scheduler entity with values
+func newHoldScheduler(c clock) *holdScheduler {
+	return &holdScheduler{clock: c, timers: make(map[KeyIdentifiers]scheduledTimer)}
+}
+
+// Function schedules callback for key after delay, replacing previously scheduled one
+func (hs *holdScheduler) schedule(id KeyIdentifiers, delay time.Duration, callback func()) {
+	hs.mutex.Lock()
+	defer hs.mutex.Unlock()
+
+	if st, ok := hs.timers[id]; ok {
+		st.timer.Stop()
+	}
+
+	// GENERATION IS KNOWN BEFORE TIMER IS ARMED
+	hs.generation++
+	generation := hs.generation
+	hs.timers[id] = scheduledTimer{generation: generation}
+	t := hs.clock.AfterFunc(delay, func() {
+		hs.mutex.Lock()
+		if st, ok := hs.timers[id]; ok && st.generation == generation {
+			delete(hs.timers, id)
+		}
+		hs.mutex.Unlock()
+		callback()
+	})
+	if st, ok := hs.timers[id]; ok && st.generation == generation {
+		st.timer = t
+		hs.timers[id] = st
+	}
+}
+
+// Function cancels scheduled callback for key if present
+func (hs *holdScheduler) cancel(id KeyIdentifiers) {
+	hs.mutex.Lock()
+	defer hs.mutex.Unlock()
+
+	if st, ok := hs.timers[id]; ok {
+		st.timer.Stop()
+		delete(hs.timers, id)
+	}
+}
+
+// Function cancels all scheduled callbacks
+func (hs *holdScheduler) cancelAll() {
+	hs.mutex.Lock()
+	defer hs.mutex.Unlock()
+
+	for id, st := range hs.timers {
+		st.timer.Stop()
+		delete(hs.timers, id)
+	}
+}
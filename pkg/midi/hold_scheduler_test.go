@@ -0,0 +1,78 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/model"
+	"testing"
+	"time"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// Function checks that scheduled callback fires only after delay elapsed
+func TestHoldSchedulerFiresAfterDelay(t *testing.T) {
+	fc := newFakeClock()
+	hs := newHoldScheduler(fc)
+	fired := 0
+	hs.schedule(KeyIdentifiers{NoteKind, 0, 60}, time.Second, func() { fired++ })
+
+	fc.Advance(999 * time.Millisecond)
+	if fired != 0 {
+		t.Fatalf("callback fired before delay elapsed")
+	}
+	fc.Advance(time.Millisecond)
+	if fired != 1 {
+		t.Fatalf("expected callback to fire once, fired %d times", fired)
+	}
+	if len(hs.timers) != 0 {
+		t.Fatalf("fired timer was not removed from scheduler")
+	}
+}
+
+// Function checks that cancelled and rescheduled callbacks do not fire
+func TestHoldSchedulerCancel(t *testing.T) {
+	fc := newFakeClock()
+	hs := newHoldScheduler(fc)
+	first, second := 0, 0
+	hs.schedule(KeyIdentifiers{NoteKind, 0, 60}, time.Second, func() { first++ })
+	hs.schedule(KeyIdentifiers{NoteKind, 0, 60}, 2*time.Second, func() { second++ })
+	hs.schedule(KeyIdentifiers{NoteKind, 0, 61}, time.Second, func() { t.Fatalf("cancelled callback fired") })
+	hs.cancel(KeyIdentifiers{NoteKind, 0, 61})
+
+	fc.Advance(3 * time.Second)
+	if first != 0 || second != 1 {
+		t.Fatalf("expected only replacing callback to fire, got first=%d second=%d", first, second)
+	}
+}
+
+// Function checks that NoteHold is emitted without any further MIDI traffic
+func TestNoteHoldWithoutMidiTraffic(t *testing.T) {
+	md, fc, signals := newTestDevice(t, testDeviceConfig())
+
+	md.processMidiMessage(midi.NoteOn(0, 60, 100), 0)
+	expectSignalCodes(t, drainSignals(signals), "NotePushed")
+
+	fc.Advance(md.holdDelta - time.Millisecond)
+	expectSignalCodes(t, drainSignals(signals))
+
+	fc.Advance(time.Millisecond)
+	received := drainSignals(signals)
+	expectSignalCodes(t, received, "NoteHold")
+	if hold := received[0].(model.NoteHold); hold.KeyCode != 60 || hold.Velocity != 100 || hold.Namespace != "default" {
+		t.Fatalf("unexpected hold signal %+v", hold)
+	}
+
+	md.processMidiMessage(midi.NoteOff(0, 60), 0)
+	expectSignalCodes(t, drainSignals(signals), "NoteReleasedAfterHold")
+}
+
+// Function checks that release before hold delta cancels NoteHold
+func TestNoteReleasedCancelsHold(t *testing.T) {
+	md, fc, signals := newTestDevice(t, testDeviceConfig())
+
+	md.processMidiMessage(midi.NoteOn(0, 60, 100), 0)
+	fc.Advance(md.holdDelta / 2)
+	md.processMidiMessage(midi.NoteOff(0, 60), 0)
+	fc.Advance(md.holdDelta)
+
+	expectSignalCodes(t, drainSignals(signals), "NotePushed", "NoteReleased")
+}
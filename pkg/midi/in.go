@@ -2,124 +2,263 @@ package midi
 
 import (
 	"midi_manipulator/pkg/model"
-	"time"
 
 	"git.miem.hse.ru/hubman/hubman-lib/core"
 	"gitlab.com/gomidi/midi/v2"
 	"go.uber.org/zap"
 )
 
-// Function writes signals to provided channel for device entity
-func (md *MidiDevice) sendSignals(signals []core.Signal) {
-	for _, signal := range signals {
-		if signal != nil {
-			md.logger.Debug("Received signal from MIDI device", zap.String("signal", signal.Code()), zap.Any("payload", signal))
-			md.signals <- signal
-		}
+// Function writes stamped signals to provided channel for device entity in order of their sequence numbers
+func (md *MidiDevice) sendSignals(signals []stampedSignal) {
+	for _, stamped := range signals {
+		md.sequencer.deliver(stamped.sequence, func() {
+			md.logger.Debug("Received signal from MIDI device", zap.String("signal", stamped.signal.Code()), zap.Any("payload", stamped.signal))
+			md.signals <- stamped.signal
+		})
 	}
 }
 
 // Function processing signals from MIDI-device
-func (md *MidiDevice) processMidiMessage(msg midi.Message, _ int32) {
+func (md *MidiDevice) processMidiMessage(msg midi.Message, timestampms int32) {
 	md.mutex.Lock()
-	var channel, key, velocity uint8
+	receivedAt := md.receiveTime(timestampms)
+	var channel, key, velocity, program uint8
+	var pitchBend int16
+	var sysEx []byte
+	var signalSequence []core.Signal
 	switch {
 	case msg.GetNoteOn(&channel, &key, &velocity):
 		// NIL STATUS
-		kctx := KeyContext{key, velocity, time.Now(),
+		id := KeyIdentifiers{NoteKind, channel, key}
+		kctx := KeyContext{id, md.mapVelocity(key, velocity), md.clock.Now(),
 			nil}
-		md.clickBuffer.SetKeyContext(key, kctx)
+		md.clickBuffer.SetKeyContext(id, kctx)
 	case msg.GetNoteOff(&channel, &key, &velocity):
-		// NOTE RELEASED STATUS
-		val, ok := md.clickBuffer.GetKeyContext(key)
-		if ok {
-			switch val.status.(type) {
-			case model.NotePushed:
-				val.status = model.NoteReleased{Device: md.name, KeyCode: int(key), Velocity: int(velocity)}
-			case model.NoteHold:
-				val.status = model.NoteReleasedAfterHold{Device: md.name, KeyCode: int(key), Velocity: int(velocity)}
-			}
-		}
+		md.releaseKey(KeyIdentifiers{NoteKind, channel, key}, velocity)
 	case msg.GetControlChange(&channel, &key, &velocity):
-		// CONTROL PUSHED STATUS
-		velocity, valid := md.handleControls(int(key), int(velocity))
-		if valid {
-			kctx := KeyContext{key: key, velocity: uint8(velocity), usedAt: time.Now(),
-				status: model.ControlPushed{Device: md.name, KeyCode: int(key), Value: int(velocity)}}
-			md.clickBuffer.SetKeyContext(key, kctx)
+		signal, passed := md.decodeHighResolution(channel, key, velocity)
+		if signal != nil {
+			signalSequence = append(signalSequence, signal)
+		}
+		for _, change := range passed {
+			md.handleControlChange(channel, change.controller, change.value)
+		}
+	case msg.GetPitchBend(&channel, &pitchBend, nil):
+		signalSequence = append(signalSequence, model.PitchBendChanged{
+			Device:    md.name,
+			Namespace: md.namespace,
+			Channel:   int(channel),
+			Value:     int(pitchBend),
+		})
+	case msg.GetAfterTouch(&channel, &velocity):
+		signalSequence = append(signalSequence, model.ChannelPressureChanged{
+			Device:    md.name,
+			Namespace: md.namespace,
+			Channel:   int(channel),
+			Pressure:  int(velocity),
+		})
+	case msg.GetPolyAfterTouch(&channel, &key, &velocity):
+		signalSequence = append(signalSequence, model.PolyAftertouchChanged{
+			Device:    md.name,
+			Namespace: md.namespace,
+			Channel:   int(channel),
+			KeyCode:   int(key),
+			KeyName:   md.keyName(NoteKind, int(key)),
+			Pressure:  int(velocity),
+		})
+	case msg.GetProgramChange(&channel, &program):
+		signalSequence = append(signalSequence, model.ProgramChanged{
+			Device:    md.name,
+			Namespace: md.namespace,
+			Channel:   int(channel),
+			Program:   int(program),
+		})
+	case msg.GetSysEx(&sysEx):
+		if signal := md.sysExToSignal(msg.Bytes()); signal != nil {
+			signalSequence = append(signalSequence, signal)
+		}
+	default:
+		if signal := md.decodeTransport(msg); signal != nil {
+			signalSequence = append(signalSequence, signal)
 		}
 	}
+	stamped := md.stampSignals(append(signalSequence, md.messageToSignal()...), receivedAt)
 	md.mutex.Unlock()
 
-	md.sendSignals(md.messageToSignal())
+	md.sendSignals(stamped)
 }
 
 
-// Function converts message to hubman-compatible signal
-func (md *MidiDevice) messageToSignal() []core.Signal {
-	md.mutex.Lock()
-	defer md.mutex.Unlock()
+// Function handles control change not consumed by high resolution controls
+func (md *MidiDevice) handleControlChange(channel uint8, key uint8, velocity uint8) {
+	if md.buttonControls[int(key)] {
+		md.pushButtonControl(KeyIdentifiers{ControlKind, channel, key}, velocity)
+		return
+	}
+	if velocity, accepted := md.filterControl(channel, key, velocity); accepted {
+		md.pushControl(channel, key, velocity)
+	}
+}
+
+// Function puts released status of key to click buffer and cancels its hold detection
+func (md *MidiDevice) releaseKey(id KeyIdentifiers, velocity uint8) {
+	// NOTE RELEASED STATUS
+	md.holdScheduler.cancel(id)
+	md.repeatScheduler.cancel(id)
+	val, ok := md.clickBuffer.GetKeyContext(id)
+	if ok {
+		switch status := val.status.(type) {
+		case model.NotePushed:
+			val.status = model.NoteReleased{Device: md.name, Channel: int(id.channel), KeyCode: int(id.key), Velocity: int(velocity)}
+		case model.NoteHold:
+			val.status = model.NoteReleasedAfterHold{Device: md.name, Channel: int(id.channel), KeyCode: int(id.key), Velocity: int(velocity), Level: status.Level}
+		}
+	}
+}
+
+// Function handles value of control configured as momentary button like NoteOn and NoteOff
+func (md *MidiDevice) pushButtonControl(id KeyIdentifiers, velocity uint8) {
+	if velocity == 0 {
+		md.releaseKey(id, velocity)
+		return
+	}
+	if _, pressed := md.clickBuffer.GetKeyContext(id); pressed {
+		return // repeated value of pressed button ignored
+	}
+	// NIL STATUS
+	md.clickBuffer.SetKeyContext(id, KeyContext{id, velocity, md.clock.Now(), nil})
+}
+
+// Function handles value of control and puts it to click buffer
+func (md *MidiDevice) pushControl(channel uint8, key uint8, velocity uint8) {
+	// CONTROL PUSHED STATUS
+	value, delta, valid := md.handleControls(int(key), int(velocity))
+	if valid {
+		id := KeyIdentifiers{ControlKind, channel, key}
+		kctx := KeyContext{id: id, velocity: velocity, usedAt: md.clock.Now(),
+			status: model.ControlPushed{Device: md.name, Channel: int(channel), KeyCode: int(key), KeyName: md.keyName(ControlKind, int(key)), Value: value, Delta: delta}}
+		md.clickBuffer.SetKeyContext(id, kctx)
+	}
+}
 
+// Function converts message to hubman-compatible signal, must be called under device lock
+func (md *MidiDevice) messageToSignal() []core.Signal {
 	var signalSequence []core.Signal
-	for _, kctx := range md.clickBuffer {
-		switch kctx.status.(type) {
+	for _, kctx := range md.clickBuffer.sortedKeyContexts() {
+		switch status := kctx.status.(type) {
 		case nil:
 			signal := model.NotePushed{
 				Device:    md.name,
-				KeyCode:   int(kctx.key),
+				Channel:   int(kctx.id.channel),
+				Kind:      kctx.id.kind.String(),
+				KeyCode:   int(kctx.id.key),
+				KeyName:   md.keyName(kctx.id.kind, int(kctx.id.key)),
 				Velocity:  int(kctx.velocity),
 				Namespace: md.namespace,
 			}
 			signalSequence = append(signalSequence, signal)
+			if toggleSignal := md.toggle(kctx); toggleSignal != nil {
+				signalSequence = append(signalSequence, toggleSignal)
+			}
+			if groupSignal := md.selectGroupKey(kctx); groupSignal != nil {
+				signalSequence = append(signalSequence, groupSignal)
+			}
 			// UPDATE KEY STATUS IN BUFFER
 			kctx.status = signal
-		case model.NotePushed:
-			if time.Since(kctx.usedAt) >= md.holdDelta {
-				signal := model.NoteHold{
-					Device:    md.name,
-					KeyCode:   int(kctx.key),
-					Velocity:  int(kctx.velocity),
-					Namespace: md.namespace,
-				}
-				signalSequence = append(signalSequence, signal)
-				// UPDATE KEY STATUS IN BUFFER
-				kctx.status = signal
-			}
+			md.scheduleHold(kctx)
 		case model.NoteReleased:
 			signal := model.NoteReleased{
 				Device:    md.name,
-				KeyCode:   int(kctx.key),
+				Channel:   int(kctx.id.channel),
+				Kind:      kctx.id.kind.String(),
+				KeyCode:   int(kctx.id.key),
+				KeyName:   md.keyName(kctx.id.kind, int(kctx.id.key)),
 				Velocity:  int(kctx.velocity),
 				Namespace: md.namespace,
 			}
 			signalSequence = append(signalSequence, signal)
+			if tapSignal := md.registerTap(kctx); tapSignal != nil {
+				signalSequence = append(signalSequence, tapSignal)
+			}
 			// DELETE KEY FROM BUFFER
-			delete(md.clickBuffer, kctx.key)
+			delete(md.clickBuffer, kctx.id)
 		case model.NoteReleasedAfterHold:
 			signal := model.NoteReleasedAfterHold{
 				Device:    md.name,
-				KeyCode:   int(kctx.key),
+				Channel:   int(kctx.id.channel),
+				Kind:      kctx.id.kind.String(),
+				KeyCode:   int(kctx.id.key),
+				KeyName:   md.keyName(kctx.id.kind, int(kctx.id.key)),
 				Velocity:  int(kctx.velocity),
 				Namespace: md.namespace,
+				Level:     status.Level,
 			}
 			signalSequence = append(signalSequence, signal)
+			// HOLD INTERRUPTS TAP SEQUENCE
+			delete(md.taps, kctx.id)
 			// DELETE KEY FROM BUFFER
-			delete(md.clickBuffer, kctx.key)
+			delete(md.clickBuffer, kctx.id)
 		case model.ControlPushed:
-			signal := model.ControlPushed{
-				Device:    md.name,
-				KeyCode:   int(kctx.key),
-				Value:     int(kctx.velocity),
-				Namespace: md.namespace,
-			}
+			signal := status
+			signal.Namespace = md.namespace
 			signalSequence = append(signalSequence, signal)
 			// DELETE KEY FROM BUFFER
-			delete(md.clickBuffer, kctx.key)
+			delete(md.clickBuffer, kctx.id)
 		}
 	}
+	signalSequence = append(signalSequence, md.detectChords()...)
 	return signalSequence
 }
 
+// Function schedules hold detection for pushed key after delta of its first hold level
+func (md *MidiDevice) scheduleHold(kctx *KeyContext) {
+	levels := md.keyHoldLevels(kctx.id)
+	md.holdScheduler.schedule(kctx.id, levels[0].delta, func() {
+		md.holdKey(kctx, levels, 0)
+	})
+}
+
+// Function converts pushed key to hold state of reached level and schedules the next level
+func (md *MidiDevice) holdKey(kctx *KeyContext, levels []holdLevel, idx int) {
+	md.mutex.Lock()
+	current, ok := md.clickBuffer.GetKeyContext(kctx.id)
+	if !ok || current != kctx {
+		md.mutex.Unlock()
+		return
+	}
+	_, pushed := kctx.status.(model.NotePushed)
+	_, held := kctx.status.(model.NoteHold)
+	if (idx == 0 && !pushed) || (idx > 0 && !held) {
+		md.mutex.Unlock()
+		return
+	}
+	signal := model.NoteHold{
+		Device:    md.name,
+		Channel:   int(kctx.id.channel),
+		Kind:      kctx.id.kind.String(),
+		KeyCode:   int(kctx.id.key),
+		KeyName:   md.keyName(kctx.id.kind, int(kctx.id.key)),
+		Velocity:  int(kctx.velocity),
+		Namespace: md.namespace,
+		Level:     levels[idx].name,
+	}
+	// UPDATE KEY STATUS IN BUFFER
+	kctx.status = signal
+	signalSequence := []core.Signal{signal}
+	if idx+1 < len(levels) {
+		md.holdScheduler.schedule(kctx.id, levels[idx+1].delta-levels[idx].delta, func() {
+			md.holdKey(kctx, levels, idx+1)
+		})
+	}
+	if interval := md.repeatInterval(kctx.id); interval > 0 && idx == 0 {
+		signalSequence = append(signalSequence, md.repeatKey(kctx, interval, 1))
+	}
+	stamped := md.stampSignals(signalSequence, md.clock.Now())
+	md.mutex.Unlock()
+
+	md.sendSignals(stamped)
+}
+
 // Function listening singals from single MIDI-device
 func (md *MidiDevice) listen() {
 	stopMidiListener := func() {}
@@ -134,7 +273,10 @@ func (md *MidiDevice) listen() {
 				continue
 			}
 			var err error
-			stopMidiListener, err = midi.ListenTo(md.ports.in, md.processMidiMessage, midi.UseSysEx())
+			md.mutex.Lock()
+			md.timestamps = driverTimestamps{}
+			md.mutex.Unlock()
+			stopMidiListener, err = midi.ListenTo(md.ports.in, md.processMidiMessage, midi.UseSysEx(), midi.UseTimeCode())
 			if err != nil {
 				md.logger.Warn("error in init listen", zap.Error(err))
 			}
@@ -0,0 +1,34 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/model"
+	"testing"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// Function checks that channel voice messages besides notes and controls are converted to signals
+func TestChannelVoiceSignals(t *testing.T) {
+	md, _, signals := newTestDevice(t, testDeviceConfig())
+	md.namespace = "mixer"
+
+	md.processMidiMessage(midi.Pitchbend(2, -4096), 0)
+	md.processMidiMessage(midi.AfterTouch(2, 64), 0)
+	md.processMidiMessage(midi.PolyAfterTouch(2, 60, 32), 0)
+	md.processMidiMessage(midi.ProgramChange(2, 5), 0)
+
+	received := drainSignals(signals)
+	expectSignalCodes(t, received, "PitchBendChanged", "ChannelPressureChanged", "PolyAftertouchChanged", "ProgramChanged")
+	if pitch := received[0].(model.PitchBendChanged); pitch.Value != -4096 || pitch.Channel != 2 || pitch.Namespace != "mixer" {
+		t.Fatalf("unexpected pitch bend signal %+v", pitch)
+	}
+	if pressure := received[1].(model.ChannelPressureChanged); pressure.Pressure != 64 {
+		t.Fatalf("unexpected channel pressure signal %+v", pressure)
+	}
+	if poly := received[2].(model.PolyAftertouchChanged); poly.KeyCode != 60 || poly.Pressure != 32 {
+		t.Fatalf("unexpected poly aftertouch signal %+v", poly)
+	}
+	if program := received[3].(model.ProgramChanged); program.Program != 5 {
+		t.Fatalf("unexpected program change signal %+v", program)
+	}
+}
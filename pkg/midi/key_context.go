@@ -7,7 +7,7 @@ import (
 
 // Representation of key context entity
 type KeyContext struct {
-	key      uint8
+	id       KeyIdentifiers
 	velocity uint8
 	usedAt   time.Time
 	status   core.Signal
@@ -0,0 +1,73 @@
+package midi
+
+import (
+	"fmt"
+	"midi_manipulator/pkg/model"
+)
+
+// Function applies configuration of key and control names to MIDI-device entity
+func (md *MidiDevice) applyKeyNames(keyNames map[int]string, controlNames map[int]string) {
+	md.keyNames = make(map[KeyKind]map[int]string)
+	md.keyCodes = make(map[KeyKind]map[string]int)
+	for kind, names := range map[KeyKind]map[int]string{NoteKind: keyNames, ControlKind: controlNames} {
+		md.keyNames[kind] = make(map[int]string, len(names))
+		md.keyCodes[kind] = make(map[string]int, len(names))
+		for key, name := range names {
+			md.keyNames[kind][key] = name
+			md.keyCodes[kind][name] = key
+		}
+	}
+}
+
+// Function returns logical name of key of given kind or empty string if key has no name
+func (md *MidiDevice) keyName(kind KeyKind, key int) string {
+	return md.keyNames[kind][key]
+}
+
+// Function returns key code of given kind by logical name if provided, otherwise given key code
+func (md *MidiDevice) resolveKeyCode(kind KeyKind, keyCode int, keyName string) (int, error) {
+	if keyName == "" {
+		return keyCode, nil
+	}
+	key, ok := md.keyCodes[kind][keyName]
+	if !ok {
+		return 0, fmt.Errorf("device {%s} has no %s with name {%s}", md.name, kind, keyName)
+	}
+	return key, nil
+}
+
+// Function replaces key name of command with key code
+func (md *MidiDevice) resolveCommandKey(command model.MidiCommand) (model.MidiCommand, error) {
+	var err error
+	switch cmd := command.(type) {
+	case model.TurnLightOnCommand:
+		cmd.KeyCode, err = md.resolveKeyCode(NoteKind, cmd.KeyCode, cmd.KeyName)
+		return cmd, err
+	case model.TurnLightOffCommand:
+		cmd.KeyCode, err = md.resolveKeyCode(NoteKind, cmd.KeyCode, cmd.KeyName)
+		return cmd, err
+	case model.SingleBlinkCommand:
+		cmd.KeyCode, err = md.resolveKeyCode(NoteKind, cmd.KeyCode, cmd.KeyName)
+		return cmd, err
+	case model.SingleReversedBlinkCommand:
+		cmd.KeyCode, err = md.resolveKeyCode(NoteKind, cmd.KeyCode, cmd.KeyName)
+		return cmd, err
+	case model.ContinuousBlinkCommand:
+		cmd.KeyCode, err = md.resolveKeyCode(NoteKind, cmd.KeyCode, cmd.KeyName)
+		return cmd, err
+	case model.StartBlinkingCommand:
+		cmd.KeyCode, err = md.resolveKeyCode(NoteKind, cmd.KeyCode, cmd.KeyName)
+		return cmd, err
+	case model.StopBlinkingCommand:
+		cmd.KeyCode, err = md.resolveKeyCode(NoteKind, cmd.KeyCode, cmd.KeyName)
+		return cmd, err
+	case model.SetControlValueCommand:
+		cmd.KeyCode, err = md.resolveKeyCode(ControlKind, cmd.KeyCode, cmd.KeyName)
+		return cmd, err
+	case model.GetControlValueCommand:
+		cmd.KeyCode, err = md.resolveKeyCode(ControlKind, cmd.KeyCode, cmd.KeyName)
+		return cmd, err
+	default:
+		return command, nil
+	}
+}
@@ -0,0 +1,83 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/config"
+	"midi_manipulator/pkg/model"
+	"testing"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// Function checks that signals carry logical key name next to key code
+func TestKeyNamesInSignals(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.KeyNames = map[int]string{94: "play"}
+	md, _, signals := newTestDevice(t, conf)
+
+	md.processMidiMessage(midi.NoteOn(0, 94, 100), 0)
+	md.processMidiMessage(midi.NoteOff(0, 94), 0)
+	md.processMidiMessage(midi.NoteOn(0, 95, 100), 0)
+
+	received := drainSignals(signals)
+	expectSignalCodes(t, received, "NotePushed", "NoteReleased", "NotePushed")
+	if pushed := received[0].(model.NotePushed); pushed.KeyCode != 94 || pushed.KeyName != "play" {
+		t.Fatalf("expected named key play, got %+v", pushed)
+	}
+	if released := received[1].(model.NoteReleased); released.KeyName != "play" {
+		t.Fatalf("expected named key play, got %+v", released)
+	}
+	if pushed := received[2].(model.NotePushed); pushed.KeyName != "" {
+		t.Fatalf("expected unnamed key, got %+v", pushed)
+	}
+}
+
+// Function checks that names of notes and controls with same number don't mix
+func TestKeyNamesScopedByKind(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.KeyNames = map[int]string{64: "pad"}
+	conf.ControlNames = map[int]string{64: "sustain", 16: "volume"}
+	conf.ButtonControls = []int{64}
+	conf.Controls = []config.Controls{{Keys: []int{16}, ValueRange: [2]int{0, 127}, InitialValue: 7, EncoderMode: config.EncoderAbsolute}}
+	md, _, signals := newTestDevice(t, conf)
+
+	md.processMidiMessage(midi.NoteOn(0, 64, 100), 0)
+	md.processMidiMessage(midi.ControlChange(0, 64, 127), 0)
+
+	received := drainSignals(signals)
+	expectSignalCodes(t, received, "NotePushed", "NotePushed")
+	if pushed := received[0].(model.NotePushed); pushed.Kind != model.KeyKindNote || pushed.KeyName != "pad" {
+		t.Fatalf("expected note named pad, got %+v", pushed)
+	}
+	if pushed := received[1].(model.NotePushed); pushed.Kind != model.KeyKindControl || pushed.KeyName != "sustain" {
+		t.Fatalf("expected control button named sustain, got %+v", pushed)
+	}
+
+	reported, err := md.executeCommand(model.GetControlValueCommand{KeyName: "volume"}, nil)
+	if err != nil || len(reported) != 1 {
+		t.Fatalf("expected value of control named volume, got %+v (%v)", reported, err)
+	}
+	if value := reported[0].(model.ControlValueReported); value.KeyCode != 16 || value.KeyName != "volume" || value.Value != 7 {
+		t.Fatalf("unexpected reported value %+v", value)
+	}
+	if _, err := md.executeCommand(model.GetControlValueCommand{KeyName: "pad"}, nil); err == nil {
+		t.Fatalf("expected error for note name in control command")
+	}
+}
+
+// Function checks that commands address keys by logical name
+func TestKeyNamesInCommands(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.KeyNames = map[int]string{3: "play"}
+	md, _, _ := newTestDevice(t, conf)
+	out := attachTestBacklight(t, md)
+
+	if err := md.ExecuteCommand(model.TurnLightOnCommand{KeyName: "play", ColorName: "green"}, md.backlightConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectSent(t, out.drain(), []byte{0x90, 3, 0x02})
+
+	if err := md.ExecuteCommand(model.TurnLightOnCommand{KeyName: "stop", ColorName: "green"}, md.backlightConfig); err == nil {
+		t.Fatalf("expected error for unknown key name")
+	}
+	expectSent(t, out.drain())
+}
@@ -0,0 +1,71 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/config"
+	"midi_manipulator/pkg/model"
+	"time"
+
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+)
+
+// Representation of auto-repeat entity for range of keys
+type keyRepeat struct {
+	allKeys  bool
+	keyRange [2]uint8
+	interval time.Duration
+}
+
+// Function applies configuration of auto-repeat to MIDI-device entity
+func (md *MidiDevice) applyKeyRepeats(repeatList []config.KeyRepeat) {
+	md.keyRepeats = make([]keyRepeat, 0, len(repeatList))
+	for _, repeat := range repeatList {
+		kr := keyRepeat{
+			allKeys:  len(repeat.KeyRange) == 0,
+			interval: time.Duration(repeat.Interval) * time.Millisecond,
+		}
+		if !kr.allKeys {
+			kr.keyRange = [2]uint8{uint8(repeat.KeyRange[0]), uint8(repeat.KeyRange[1])}
+		}
+		md.keyRepeats = append(md.keyRepeats, kr)
+	}
+}
+
+// Function returns auto-repeat interval of key from first matching configuration or zero if key isn't repeated
+func (md *MidiDevice) repeatInterval(id KeyIdentifiers) time.Duration {
+	for _, repeat := range md.keyRepeats {
+		if repeat.allKeys || (repeat.keyRange[0] <= id.key && id.key <= repeat.keyRange[1]) {
+			return repeat.interval
+		}
+	}
+	return 0
+}
+
+// Function returns repeat signal of held key and schedules the next one after interval
+func (md *MidiDevice) repeatKey(kctx *KeyContext, interval time.Duration, count int) core.Signal {
+	md.repeatScheduler.schedule(kctx.id, interval, func() {
+		md.mutex.Lock()
+		current, ok := md.clickBuffer.GetKeyContext(kctx.id)
+		if !ok || current != kctx {
+			md.mutex.Unlock()
+			return
+		}
+		if _, held := kctx.status.(model.NoteHold); !held {
+			md.mutex.Unlock()
+			return
+		}
+		stamped := md.stampSignals([]core.Signal{md.repeatKey(kctx, interval, count+1)}, md.clock.Now())
+		md.mutex.Unlock()
+
+		md.sendSignals(stamped)
+	})
+	return model.NoteRepeat{
+		Device:      md.name,
+		Namespace:   md.namespace,
+		Channel:     int(kctx.id.channel),
+		Kind:        kctx.id.kind.String(),
+		KeyCode:     int(kctx.id.key),
+		KeyName:     md.keyName(kctx.id.kind, int(kctx.id.key)),
+		Velocity:    int(kctx.velocity),
+		RepeatCount: count,
+	}
+}
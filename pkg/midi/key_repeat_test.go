@@ -0,0 +1,42 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/config"
+	"midi_manipulator/pkg/model"
+	"testing"
+	"time"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// Function checks that held key is repeated with interval until release
+func TestKeyRepeat(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.Repeat = []config.KeyRepeat{{KeyRange: []int{10, 20}, Interval: 100}}
+	md, fc, signals := newTestDevice(t, conf)
+
+	md.processMidiMessage(midi.NoteOn(0, 15, 100), 0)
+	fc.Advance(1250 * time.Millisecond)
+	md.processMidiMessage(midi.NoteOff(0, 15), 0)
+	fc.Advance(time.Second)
+
+	received := drainSignals(signals)
+	expectSignalCodes(t, received,
+		"NotePushed", "NoteHold", "NoteRepeat", "NoteRepeat", "NoteRepeat", "NoteReleasedAfterHold")
+	if repeat := received[4].(model.NoteRepeat); repeat.RepeatCount != 3 || repeat.KeyCode != 15 {
+		t.Fatalf("expected third repeat of key 15, got %+v", repeat)
+	}
+}
+
+// Function checks that keys outside of repeat range are only held
+func TestKeyRepeatOutsideRange(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.Repeat = []config.KeyRepeat{{KeyRange: []int{10, 20}, Interval: 100}}
+	md, fc, signals := newTestDevice(t, conf)
+
+	md.processMidiMessage(midi.NoteOn(0, 30, 100), 0)
+	fc.Advance(1500 * time.Millisecond)
+	md.processMidiMessage(midi.NoteOff(0, 30), 0)
+
+	expectSignalCodes(t, drainSignals(signals), "NotePushed", "NoteHold", "NoteReleasedAfterHold")
+}
@@ -6,6 +6,7 @@ import (
 	"midi_manipulator/pkg/backlight"
 	"midi_manipulator/pkg/config"
 	"midi_manipulator/pkg/model"
+	"midi_manipulator/pkg/state"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -27,7 +28,16 @@ type MidiDevice struct {
 	active             bool
 	ports              MidiPorts
 	clickBuffer        ClickBuffer
+	clock              clock
+	holdScheduler      *holdScheduler
+	repeatScheduler    *holdScheduler
 	holdDelta          time.Duration
+	holdLevels         []holdLevel
+	keyRepeats         []keyRepeat
+	tapWindow          time.Duration
+	taps               map[KeyIdentifiers]tapContext
+	toggles            map[int]*toggleKey
+	keyGroups          []*keyGroup
 	startupDelay       time.Duration
 	reconnectInterval  time.Duration
 	mutex              sync.Mutex
@@ -37,7 +47,21 @@ type MidiDevice struct {
 	namespace          string
 	connected          atomic.Bool
 	controls           map[int]*Control
+	controlFilters     map[int]*controlFilter
+	buttonControls     map[int]bool
+	controlBanks       map[string]map[int]int
+	stateStore         state.Store
+	backlightConfig    *backlight.DeviceBacklightConfig
+	chords             []*chordContext
+	sysExPatterns      []sysExPattern
+	highResolution     *highResolutionDecoder
+	transport          transportContext
+	velocityCurves     []velocityCurve
+	keyNames           map[KeyKind]map[int]string
+	keyCodes           map[KeyKind]map[string]int
 	signals            chan<- core.Signal
+	timestamps         driverTimestamps
+	sequencer          *sequencer
 	logger             *zap.Logger
 	conf               config.DeviceConfig
 	reconnectedEvent   chan bool
@@ -60,6 +84,17 @@ type MidiPorts struct {
 	out drivers.Out
 }
 
+// Function sets storage used to persist accumulated values of controls and restores values of active namespace
+func (md *MidiDevice) SetStateStore(store state.Store) {
+	md.mutex.Lock()
+	defer md.mutex.Unlock()
+
+	md.stateStore = store
+	// BANKS LOADED WITHOUT STORE ARE DROPPED
+	md.controlBanks = make(map[string]map[int]int)
+	md.loadControlBank(md.namespace)
+}
+
 // Function returns alias of MIDI-device
 func (md *MidiDevice) GetAlias() string {
 	return md.name
@@ -68,7 +103,23 @@ func (md *MidiDevice) GetAlias() string {
 // Function executes command on MIDI-device
 func (md *MidiDevice) ExecuteCommand(command model.MidiCommand, backlightConfig *backlight.DeviceBacklightConfig) error {
 	md.mutex.Lock()
-	defer md.mutex.Unlock()
+	signalSequence, err := md.executeCommand(command, backlightConfig)
+	stamped := md.stampSignals(signalSequence, md.clock.Now())
+	md.mutex.Unlock()
+
+	md.sendSignals(stamped)
+	return err
+}
+
+// Function executes command on MIDI-device under lock and returns signals to be sent after unlock
+func (md *MidiDevice) executeCommand(
+	command model.MidiCommand,
+	backlightConfig *backlight.DeviceBacklightConfig,
+) ([]core.Signal, error) {
+	command, err := md.resolveCommandKey(command)
+	if err != nil {
+		return nil, err
+	}
 
 	switch cmd := command.(type) {
 	case model.TurnLightOnCommand:
@@ -80,7 +131,7 @@ func (md *MidiDevice) ExecuteCommand(command model.MidiCommand, backlightConfig
 	case model.SingleReversedBlinkCommand:
 		md.singleReversedBlink(cmd, backlightConfig)
 	case model.SetActiveNamespaceCommand:
-		md.setActiveNamespace(cmd, backlightConfig)
+		return md.setActiveNamespace(cmd, backlightConfig), nil
 	case model.StartBlinkingCommand:
 		md.blinkingQueueMutex.Lock()
 		md.blinkingKeys[cmd.KeyCode] = blinkingKey{
@@ -94,10 +145,14 @@ func (md *MidiDevice) ExecuteCommand(command model.MidiCommand, backlightConfig
 		md.blinkingQueueMutex.Lock()
 		delete(md.blinkingKeys, cmd.KeyCode)
 		md.blinkingQueueMutex.Unlock()
+	case model.SetControlValueCommand:
+		return nil, md.setControlValue(cmd)
+	case model.GetControlValueCommand:
+		return md.getControlValue(cmd)
 	default:
 		md.logger.Warn("Unknown command", zap.Any("command", cmd))
 	}
-	return nil
+	return nil, nil
 }
 
 // Function frees resources of current MIDI-device
@@ -105,10 +160,18 @@ func (md *MidiDevice) Stop() {
 	close(md.stopListen)
 	close(md.stopReconnect)
 	close(md.stopBlinking)
+	md.holdScheduler.cancelAll()
+	md.repeatScheduler.cancelAll()
+	md.mutex.Lock()
+	md.stopControlFilters()
+	md.mutex.Unlock()
 }
 
 // Function initialized working process for MIDI-device
 func (md *MidiDevice) RunDevice(backlightConfig *backlight.DeviceBacklightConfig) {
+	md.mutex.Lock()
+	md.backlightConfig = backlightConfig
+	md.mutex.Unlock()
 	time.Sleep(md.startupDelay)
 	go md.reconnect(backlightConfig)
 	go md.listen()
@@ -124,8 +187,16 @@ func (md *MidiDevice) initConnection(backlightConfig *backlight.DeviceBacklightC
 		return err
 	}
 	go md.startupIllumination(backlightConfig)
-	md.clickBuffer = make(map[uint8]*KeyContext)
+	md.holdScheduler.cancelAll()
+	md.repeatScheduler.cancelAll()
+	md.clickBuffer = make(ClickBuffer)
+	md.taps = make(map[KeyIdentifiers]tapContext)
 	md.applyControls(md.conf.Controls)
+	md.applyControlFilters(md.conf.ControlFilters)
+	md.loadControlBank(md.namespace)
+	md.applyChords(md.conf.Chords)
+	md.highResolution = newHighResolutionDecoder(md.conf.HighResolution)
+	md.transport = transportContext{}
 	return nil
 }
 
@@ -255,19 +326,37 @@ func (md *MidiDevice) applyConfiguration(
 	md.name = deviceConfig.DeviceName
 	md.active = deviceConfig.Active
 	md.holdDelta = time.Duration(deviceConfig.HoldDelta) * time.Millisecond
+	md.tapWindow = time.Duration(deviceConfig.TapWindow) * time.Millisecond
+	md.taps = make(map[KeyIdentifiers]tapContext)
 	md.startupDelay = time.Duration(deviceConfig.StartupDelay) * time.Millisecond
 	md.reconnectInterval = time.Duration(deviceConfig.ReconnectInterval) * time.Millisecond
-	md.clickBuffer = make(map[uint8]*KeyContext)
+	md.clickBuffer = make(ClickBuffer)
+	md.clock = systemClock{}
+	md.holdScheduler = newHoldScheduler(md.clock)
+	md.repeatScheduler = newHoldScheduler(md.clock)
 	md.stopListen = make(chan struct{})
 	md.stopReconnect = make(chan struct{})
 	md.stopBlinking = make(chan struct{})
 	md.reconnectedEvent = make(chan bool)
 	md.blinkingKeys = make(map[int]blinkingKey)
 	md.namespace = deviceConfig.Namespace
+	md.controlBanks = make(map[string]map[int]int)
 	md.signals = signals
 	md.logger = logger.With(zap.String("alias", md.name))
 	md.checkManager = checkManager
 	md.applyControls(deviceConfig.Controls)
+	md.loadControlBank(md.namespace)
+	md.applyControlFilters(deviceConfig.ControlFilters)
+	md.applyButtonControls(deviceConfig.ButtonControls)
+	md.applyToggleKeys(deviceConfig.ToggleKeys)
+	md.applyKeyGroups(deviceConfig.KeyGroups)
+	md.applyChords(deviceConfig.Chords)
+	md.applySysExSignals(deviceConfig.SysExSignals)
+	md.highResolution = newHighResolutionDecoder(deviceConfig.HighResolution)
+	md.applyVelocityCurves(deviceConfig.VelocityCurves)
+	md.applyHoldLevels(deviceConfig.HoldLevels)
+	md.applyKeyRepeats(deviceConfig.Repeat)
+	md.applyKeyNames(deviceConfig.KeyNames, deviceConfig.ControlNames)
 }
 
 
@@ -279,16 +368,36 @@ func (md *MidiDevice) applyControls(controlsList []config.Controls) {
 			control := Control{
 				Key:              controlKey,
 				Rotate:           controls.Rotate,
+				Passthrough:      controls.Passthrough,
 				ValueRange:       controls.ValueRange,
 				InitialValue:     controls.InitialValue,
+				DefaultValue:     controls.InitialValue,
 				DecrementTrigger: controls.Triggers.Decrement,
 				IncrementTrigger: controls.Triggers.Increment,
+				EncoderMode:      controls.EncoderMode,
+				Pickup:           controls.Pickup,
+				LastPosition:     -1,
+				Step:             controls.Step,
+				Acceleration:     append([]config.AccelerationPoint(nil), controls.Acceleration...),
+				Feedback:         controls.Feedback,
+			}
+			control.sortAcceleration()
+			if control.Step == 0 {
+				control.Step = 1
 			}
 			md.controls[controlKey] = &control
 		}
 	}
 }
 
+// Function applies configuration of controls working as momentary buttons to MIDI-device entity
+func (md *MidiDevice) applyButtonControls(buttonControls []int) {
+	md.buttonControls = make(map[int]bool, len(buttonControls))
+	for _, key := range buttonControls {
+		md.buttonControls[key] = true
+	}
+}
+
 // Function initializes midi device entity with values
 func NewDevice(
 	deviceConfig config.DeviceConfig,
@@ -296,20 +405,19 @@ func NewDevice(
 	logger *zap.Logger,
 	checkManager core.CheckRegistry,
 ) *MidiDevice {
-	midiDevice := MidiDevice{}
+	midiDevice := MidiDevice{sequencer: newSequencer()}
 	midiDevice.applyConfiguration(deviceConfig, signals, logger, checkManager)
 
 	return &midiDevice
 }
 
-// Function sends callback signals after namespace is changed
-func (md *MidiDevice) sendNamespaceChangedSignal(signals chan<- core.Signal, oldNamespace string, newNamespace string) {
-	signal := model.NamespaceChanged{
+// Function returns callback signal after namespace is changed
+func (md *MidiDevice) namespaceChangedSignal(oldNamespace string, newNamespace string) core.Signal {
+	return model.NamespaceChanged{
 		Device:       md.name,
 		OldNamespace: oldNamespace,
 		NewNamespace: newNamespace,
 	}
-	signals <- signal
 }
 
 // Function contains logic of continuous blinking for single component of MIDI-device
@@ -0,0 +1,132 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/backlight"
+	"midi_manipulator/pkg/config"
+	"testing"
+
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+	"go.uber.org/zap"
+)
+
+// Function initializes MIDI-device entity driven by fake clock for tests
+func newTestDevice(t *testing.T, deviceConfig config.DeviceConfig) (*MidiDevice, *fakeClock, chan core.Signal) {
+	t.Helper()
+	signals := make(chan core.Signal, 64)
+	md := NewDevice(deviceConfig, signals, zap.NewNop(), core.NewCheckManager())
+	fc := newFakeClock()
+	md.clock = fc
+	md.holdScheduler = newHoldScheduler(fc)
+	md.repeatScheduler = newHoldScheduler(fc)
+	return md, fc, signals
+}
+
+// Function returns default device configuration for tests
+func testDeviceConfig() config.DeviceConfig {
+	return config.DeviceConfig{
+		DeviceName:        "test",
+		ReconnectInterval: config.MinReconnectIntervalMs,
+		Active:            true,
+		HoldDelta:         1000,
+		Namespace:         "default",
+	}
+}
+
+// Function collects all signals currently waiting in channel
+func drainSignals(signals chan core.Signal) []core.Signal {
+	var received []core.Signal
+	for {
+		select {
+		case signal := <-signals:
+			received = append(received, signal)
+		default:
+			return received
+		}
+	}
+}
+
+// Function checks that received signals have expected codes in order
+func expectSignalCodes(t *testing.T, received []core.Signal, codes ...string) {
+	t.Helper()
+	if len(received) != len(codes) {
+		t.Fatalf("expected %d signals %v, got %d: %v", len(codes), codes, len(received), received)
+	}
+	for idx, code := range codes {
+		if received[idx].Code() != code {
+			t.Fatalf("signal #%d: expected %s, got %s (%v)", idx, code, received[idx].Code(), received[idx])
+		}
+	}
+}
+
+// Representation of MIDI out port recording sent messages
+type fakeOutPort struct {
+	sent [][]byte
+}
+
+// Functions implementing drivers.Port for fake out port
+func (p *fakeOutPort) Open() error             { return nil }
+func (p *fakeOutPort) Close() error            { return nil }
+func (p *fakeOutPort) IsOpen() bool            { return true }
+func (p *fakeOutPort) Number() int             { return 0 }
+func (p *fakeOutPort) String() string          { return "test" }
+func (p *fakeOutPort) Underlying() interface{} { return nil }
+
+// Function records sent message
+func (p *fakeOutPort) Send(data []byte) error {
+	p.sent = append(p.sent, append([]byte(nil), data...))
+	return nil
+}
+
+// Function returns recorded messages and forgets them
+func (p *fakeOutPort) drain() [][]byte {
+	sent := p.sent
+	p.sent = nil
+	return sent
+}
+
+// Backlight configuration of test device with keys 0-15 lit by NoteOn and turned off by NoteOff
+const testBacklightConfig = `
+device_light_configuration:
+  - device_name: test
+    backlight_time_offset: 0
+    color_spaces:
+      - color_space_id: 1
+        on:
+          - color_name: red
+            payload: 01
+          - color_name: green
+            payload: 02
+          - color_name: blue
+            payload: 03
+        off:
+          - color_name: black
+            payload: 00
+    keyboard_backlight:
+      - key_range:
+          - 0
+          - 15
+        key_number_shift: 0
+        color_space: 1
+        statuses:
+          on:
+            type: NoteOn
+            fallback_color: red
+            bytes: 90 %key %payload
+          off:
+            type: NoteOff
+            fallback_color: black
+            bytes: 80 %key %payload
+`
+
+// Function attaches fake out port and test backlight configuration to device
+func attachTestBacklight(t *testing.T, md *MidiDevice) *fakeOutPort {
+	t.Helper()
+	backlightConfig, err := backlight.ParseConfigFromBytes([]byte(testBacklightConfig))
+	if err != nil {
+		t.Fatalf("unable to parse test backlight config: %v", err)
+	}
+	out := &fakeOutPort{}
+	md.ports.out = out
+	md.backlightConfig = backlightConfig
+	return out
+}
@@ -1,9 +1,12 @@
 package midi
 
 import (
+	"fmt"
 	"midi_manipulator/pkg/backlight"
 	"midi_manipulator/pkg/model"
 	"time"
+
+	"git.miem.hse.ru/hubman/hubman-lib/core"
 )
 
 // Function handles logic of turn light on command
@@ -57,10 +60,47 @@ func (md *MidiDevice) singleReversedBlink(
 func (md *MidiDevice) setActiveNamespace(
 	cmd model.SetActiveNamespaceCommand,
 	_ *backlight.DeviceBacklightConfig,
-) {
+) []core.Signal {
 	oldNamespace := md.namespace
 	md.namespace = cmd.Namespace
-	md.sendNamespaceChangedSignal(md.signals, oldNamespace, cmd.Namespace)
+	md.loadControlBank(cmd.Namespace)
+	return append([]core.Signal{md.namespaceChangedSignal(oldNamespace, cmd.Namespace)}, md.controlValueSignals()...)
+}
+
+// Function returns accumulated control by key or error if key isn't configured as accumulate control
+func (md *MidiDevice) getAccumulatedControl(key int) (*Control, error) {
+	control, ok := md.controls[key]
+	if !ok || control.Passthrough {
+		return nil, fmt.Errorf("device {%s} has no accumulate control with key {%d}", md.name, key)
+	}
+	return control, nil
+}
+
+// Function handles logic of set control value command
+func (md *MidiDevice) setControlValue(cmd model.SetControlValueCommand) error {
+	control, err := md.getAccumulatedControl(cmd.KeyCode)
+	if err != nil {
+		return err
+	}
+	control.InitialValue = max(control.ValueRange[0], min(cmd.Value, control.ValueRange[1]))
+	control.PickedUp = false
+	md.storeControlValue(cmd.KeyCode, control.InitialValue)
+	return nil
+}
+
+// Function handles logic of get control value command
+func (md *MidiDevice) getControlValue(cmd model.GetControlValueCommand) ([]core.Signal, error) {
+	control, err := md.getAccumulatedControl(cmd.KeyCode)
+	if err != nil {
+		return nil, err
+	}
+	return []core.Signal{model.ControlValueReported{
+		Device:    md.name,
+		Namespace: md.namespace,
+		KeyCode:   cmd.KeyCode,
+		KeyName:   md.keyName(ControlKind, cmd.KeyCode),
+		Value:     control.InitialValue,
+	}}, nil
 }
 
 // Function handles logic of turning light for range of keys of single MIDI-device
@@ -89,4 +129,10 @@ func (md *MidiDevice) startupIllumination(config *backlight.DeviceBacklightConfi
 		md.turnLightKeyRange(config, keyRange[0], keyRange[1], backlight.On, backlightTimeOffset)
 		md.turnLightKeyRange(config, keyRange[0], keyRange[1], backlight.Off, backlightTimeOffset)
 	}
+
+	md.mutex.Lock()
+	md.showAllControlFeedback()
+	md.showAllToggles()
+	md.showAllKeyGroups()
+	md.mutex.Unlock()
 }
@@ -0,0 +1,158 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/model"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+)
+
+// Representation of driver timestamps anchored to wall clock at first received message
+type driverTimestamps struct {
+	base    time.Time
+	last    int32
+	elapsed int64
+}
+
+// Function returns receive time of message by driver timestamp in milliseconds since first received message
+func (md *MidiDevice) receiveTime(timestampms int32) time.Time {
+	ts := &md.timestamps
+	if ts.base.IsZero() {
+		// DRIVER COUNTS FROM FIRST MESSAGE, NOT FROM START OF LISTENING
+		ts.base = md.clock.Now().Add(-time.Duration(timestampms) * time.Millisecond)
+		ts.last = timestampms
+		ts.elapsed = int64(timestampms)
+	}
+	// UNSIGNED DIFFERENCE KEEPS ELAPSED TIME MONOTONIC WHEN COUNTER WRAPS
+	ts.elapsed += int64(uint32(timestampms) - uint32(ts.last))
+	ts.last = timestampms
+	return ts.base.Add(time.Duration(ts.elapsed) * time.Millisecond)
+}
+
+// Representation of per-device signal sequence kept across re-creation of device
+type sequencer struct {
+	assigned  atomic.Int64
+	delivered int64
+	mutex     sync.Mutex
+	turn      *sync.Cond
+}
+
+// Function initializing sequencer entity
+func newSequencer() *sequencer {
+	s := &sequencer{}
+	s.turn = sync.NewCond(&s.mutex)
+	return s
+}
+
+// Function waits until all signals with lower sequence are delivered and sends signal with provided sequence
+func (s *sequencer) deliver(sequence int64, send func()) {
+	s.mutex.Lock()
+	for s.delivered != sequence-1 {
+		s.turn.Wait()
+	}
+	s.mutex.Unlock()
+
+	send()
+
+	s.mutex.Lock()
+	s.delivered = sequence
+	s.turn.Broadcast()
+	s.mutex.Unlock()
+}
+
+// Representation of signal stamped with its sequence number
+type stampedSignal struct {
+	signal   core.Signal
+	sequence int64
+}
+
+// Function stamps signals with receive time and next sequence numbers, must be called under device lock
+func (md *MidiDevice) stampSignals(signals []core.Signal, receivedAt time.Time) []stampedSignal {
+	var stamped []stampedSignal
+	for _, signal := range signals {
+		if signal != nil {
+			sequence := md.sequencer.assigned.Add(1)
+			stamped = append(stamped, stampedSignal{stampSignal(signal, receivedAt.UnixMilli(), sequence), sequence})
+		}
+	}
+	return stamped
+}
+
+// Function returns copy of signal with receive timestamp in unix milliseconds and sequence number
+func stampSignal(signal core.Signal, timestamp int64, sequence int64) core.Signal {
+	switch s := signal.(type) {
+	case model.NotePushed:
+		s.Timestamp, s.Sequence = timestamp, sequence
+		return s
+	case model.NoteHold:
+		s.Timestamp, s.Sequence = timestamp, sequence
+		return s
+	case model.NoteRepeat:
+		s.Timestamp, s.Sequence = timestamp, sequence
+		return s
+	case model.NoteReleased:
+		s.Timestamp, s.Sequence = timestamp, sequence
+		return s
+	case model.NoteReleasedAfterHold:
+		s.Timestamp, s.Sequence = timestamp, sequence
+		return s
+	case model.NoteToggled:
+		s.Timestamp, s.Sequence = timestamp, sequence
+		return s
+	case model.GroupSelectionChanged:
+		s.Timestamp, s.Sequence = timestamp, sequence
+		return s
+	case model.NoteDoubleTapped:
+		s.Timestamp, s.Sequence = timestamp, sequence
+		return s
+	case model.NoteMultiTapped:
+		s.Timestamp, s.Sequence = timestamp, sequence
+		return s
+	case model.ChordPressed:
+		s.Timestamp, s.Sequence = timestamp, sequence
+		return s
+	case model.ChordReleased:
+		s.Timestamp, s.Sequence = timestamp, sequence
+		return s
+	case model.ControlPushed:
+		s.Timestamp, s.Sequence = timestamp, sequence
+		return s
+	case model.ControlValueReported:
+		s.Timestamp, s.Sequence = timestamp, sequence
+		return s
+	case model.HighResolutionControlPushed:
+		s.Timestamp, s.Sequence = timestamp, sequence
+		return s
+	case model.PitchBendChanged:
+		s.Timestamp, s.Sequence = timestamp, sequence
+		return s
+	case model.ChannelPressureChanged:
+		s.Timestamp, s.Sequence = timestamp, sequence
+		return s
+	case model.PolyAftertouchChanged:
+		s.Timestamp, s.Sequence = timestamp, sequence
+		return s
+	case model.ProgramChanged:
+		s.Timestamp, s.Sequence = timestamp, sequence
+		return s
+	case model.SysExReceived:
+		s.Timestamp, s.Sequence = timestamp, sequence
+		return s
+	case model.TransportStarted:
+		s.Timestamp, s.Sequence = timestamp, sequence
+		return s
+	case model.TransportStopped:
+		s.Timestamp, s.Sequence = timestamp, sequence
+		return s
+	case model.TempoChanged:
+		s.Timestamp, s.Sequence = timestamp, sequence
+		return s
+	case model.NamespaceChanged:
+		s.Timestamp, s.Sequence = timestamp, sequence
+		return s
+	default:
+		return signal
+	}
+}
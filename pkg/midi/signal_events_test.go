@@ -0,0 +1,111 @@
+package midi
+
+import (
+	"math"
+	"midi_manipulator/pkg/model"
+	"testing"
+	"time"
+
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+	"gitlab.com/gomidi/midi/v2"
+	"go.uber.org/zap"
+)
+
+// Function checks that signals carry driver receive timestamp and increasing sequence number
+func TestSignalTimestampAndSequence(t *testing.T) {
+	md, fc, signals := newTestDevice(t, testDeviceConfig())
+	md.timestamps = driverTimestamps{}
+	fc.Advance(time.Second)
+	firstAt := fc.Now()
+
+	// DRIVER TIMESTAMP STARTS FROM FIRST MESSAGE AFTER IDLE GAP
+	md.processMidiMessage(midi.NoteOn(0, 1, 100), 0)
+	md.processMidiMessage(midi.NoteOff(0, 1), 150)
+
+	received := drainSignals(signals)
+	expectSignalCodes(t, received, "NotePushed", "NoteReleased")
+	pushed := received[0].(model.NotePushed)
+	released := received[1].(model.NoteReleased)
+	if pushed.Timestamp != firstAt.UnixMilli() || pushed.Sequence != 1 {
+		t.Fatalf("unexpected timestamp or sequence of %+v", pushed)
+	}
+	if released.Timestamp != firstAt.Add(150*time.Millisecond).UnixMilli() || released.Sequence != 2 {
+		t.Fatalf("unexpected timestamp or sequence of %+v", released)
+	}
+}
+
+// Function checks that receive time stays monotonic when driver timestamp wraps
+func TestReceiveTimeWrap(t *testing.T) {
+	md, fc, _ := newTestDevice(t, testDeviceConfig())
+	md.timestamps = driverTimestamps{}
+
+	firstAt := md.receiveTime(math.MaxInt32 - 10)
+	if !firstAt.Equal(fc.Now()) {
+		t.Fatalf("expected first message received at %v, got %v", fc.Now(), firstAt)
+	}
+	if wrappedAt := md.receiveTime(math.MinInt32 + 10); wrappedAt.Sub(firstAt) != 21*time.Millisecond {
+		t.Fatalf("expected 21ms after wrap, got %v", wrappedAt.Sub(firstAt))
+	}
+}
+
+// Function checks that signals are delivered in order of sequence numbers assigned under device lock
+func TestSendSignalsOrderedBySequence(t *testing.T) {
+	md, _, signals := newTestDevice(t, testDeviceConfig())
+	held := md.stampSignals([]core.Signal{model.NoteHold{Device: md.name, KeyCode: 1}}, md.clock.Now())
+	released := md.stampSignals([]core.Signal{model.NoteReleasedAfterHold{Device: md.name, KeyCode: 1}}, md.clock.Now())
+
+	// RELEASE REACHES SENDING FIRST BUT WAITS FOR HOLD
+	done := make(chan struct{})
+	go func() {
+		md.sendSignals(released)
+		close(done)
+	}()
+	select {
+	case signal := <-signals:
+		t.Fatalf("expected release to wait for hold, got %+v", signal)
+	case <-time.After(50 * time.Millisecond):
+	}
+	md.sendSignals(held)
+	<-done
+
+	received := drainSignals(signals)
+	expectSignalCodes(t, received, "NoteHold", "NoteReleasedAfterHold")
+	if received[0].(model.NoteHold).Sequence != 1 || received[1].(model.NoteReleasedAfterHold).Sequence != 2 {
+		t.Fatalf("unexpected sequences of %+v", received)
+	}
+}
+
+// Function checks that device manager keeps sequence of device when it is recreated
+func TestDeviceSequencerKeptAcrossRecreation(t *testing.T) {
+	dm := NewDeviceManager(zap.NewNop(), core.NewCheckManager())
+	md, _, signals := newTestDevice(t, testDeviceConfig())
+	md.sequencer = dm.deviceSequencer(md.name)
+	md.processMidiMessage(midi.NoteOn(0, 1, 100), 0)
+
+	recreated, _, _ := newTestDevice(t, testDeviceConfig())
+	recreated.sequencer = dm.deviceSequencer(recreated.name)
+	recreated.signals = signals
+	recreated.processMidiMessage(midi.NoteOn(0, 2, 100), 0)
+
+	received := drainSignals(signals)
+	expectSignalCodes(t, received, "NotePushed", "NotePushed")
+	if received[1].(model.NotePushed).Sequence != 2 {
+		t.Fatalf("expected sequence continued after recreation, got %+v", received[1])
+	}
+}
+
+// Function checks that signals of click buffer are emitted ordered by key identifiers
+func TestMessageToSignalOrder(t *testing.T) {
+	md, fc, _ := newTestDevice(t, testDeviceConfig())
+	for _, key := range []uint8{9, 3, 7, 1, 5} {
+		id := KeyIdentifiers{NoteKind, 0, key}
+		md.clickBuffer.SetKeyContext(id, KeyContext{id: id, velocity: 100, usedAt: fc.Now()})
+	}
+
+	signalSequence := md.messageToSignal()
+	for idx, key := range []int{1, 3, 5, 7, 9} {
+		if pushed := signalSequence[idx].(model.NotePushed); pushed.KeyCode != key {
+			t.Fatalf("signal #%d: expected key %d, got %+v", idx, key, pushed)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package midi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"midi_manipulator/pkg/config"
+	"midi_manipulator/pkg/model"
+	"strings"
+
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+	"go.uber.org/zap"
+)
+
+// Representation of kind of single token of SysEx pattern
+type sysExTokenKind uint8
+
+const (
+	sysExLiteral     sysExTokenKind = iota // exact byte, e.g. F0
+	sysExWildcard                          // any single byte, ??
+	sysExCapture                           // captured single byte, %%
+	sysExCaptureRest                       // captured sequence of any length, %*
+)
+
+// Representation of single token of SysEx pattern
+type sysExToken struct {
+	kind  sysExTokenKind
+	value byte
+}
+
+// Representation of named SysEx pattern entity
+type sysExPattern struct {
+	name   string
+	tokens []sysExToken
+}
+
+// Function decodes string representation of SysEx pattern
+func compileSysExPattern(name string, pattern string) (sysExPattern, error) {
+	compiled := sysExPattern{name: name}
+	for _, token := range strings.Fields(pattern) {
+		switch token {
+		case "??":
+			compiled.tokens = append(compiled.tokens, sysExToken{kind: sysExWildcard})
+		case "%%":
+			compiled.tokens = append(compiled.tokens, sysExToken{kind: sysExCapture})
+		case "%*":
+			compiled.tokens = append(compiled.tokens, sysExToken{kind: sysExCaptureRest})
+		default:
+			value, err := hex.DecodeString(token)
+			if err != nil || len(value) != 1 {
+				return sysExPattern{}, fmt.Errorf("invalid token {%s} in sysex pattern {%s}", token, name)
+			}
+			compiled.tokens = append(compiled.tokens, sysExToken{kind: sysExLiteral, value: value[0]})
+		}
+	}
+	return compiled, nil
+}
+
+// Function matches message with pattern and returns captured bytes
+func (p sysExPattern) match(data []byte) ([]byte, bool) {
+	return matchSysExTokens(p.tokens, data, []byte{})
+}
+
+// Function recursively matches remaining tokens with remaining bytes of message
+func matchSysExTokens(tokens []sysExToken, data []byte, captured []byte) ([]byte, bool) {
+	if len(tokens) == 0 {
+		return captured, len(data) == 0
+	}
+
+	token := tokens[0]
+	if token.kind == sysExCaptureRest {
+		for size := 0; size <= len(data); size++ {
+			withRest := append(captured[:len(captured):len(captured)], data[:size]...)
+			if result, ok := matchSysExTokens(tokens[1:], data[size:], withRest); ok {
+				return result, true
+			}
+		}
+		return nil, false
+	}
+
+	if len(data) == 0 {
+		return nil, false
+	}
+	switch token.kind {
+	case sysExLiteral:
+		if data[0] != token.value {
+			return nil, false
+		}
+	case sysExCapture:
+		captured = append(captured[:len(captured):len(captured)], data[0])
+	}
+	return matchSysExTokens(tokens[1:], data[1:], captured)
+}
+
+// Function applies configuration of SysEx signals to MIDI-device entity
+func (md *MidiDevice) applySysExSignals(sysExList []config.SysExSignal) {
+	md.sysExPatterns = make([]sysExPattern, 0, len(sysExList))
+	for _, sysEx := range sysExList {
+		pattern, err := compileSysExPattern(sysEx.Name, sysEx.Pattern)
+		if err != nil {
+			md.logger.Warn("Skipping sysex signal", zap.Error(err))
+			continue
+		}
+		md.sysExPatterns = append(md.sysExPatterns, pattern)
+	}
+}
+
+// Function converts SysEx message to signal of first matching pattern
+func (md *MidiDevice) sysExToSignal(data []byte) core.Signal {
+	for _, pattern := range md.sysExPatterns {
+		captured, ok := pattern.match(data)
+		if !ok {
+			continue
+		}
+		return model.SysExReceived{
+			Device:    md.name,
+			Namespace: md.namespace,
+			Name:      pattern.name,
+			Captured:  fmt.Sprintf("% X", captured),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package midi
+
+import (
+	"bytes"
+	"midi_manipulator/pkg/config"
+	"midi_manipulator/pkg/model"
+	"testing"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// Function checks matching of SysEx patterns with wildcards and captures
+func TestSysExPatternMatch(t *testing.T) {
+	cases := []struct {
+		pattern  string
+		data     []byte
+		matched  bool
+		captured []byte
+	}{
+		{"F0 47 ?? %% F7", []byte{0xF0, 0x47, 0x01, 0x2A, 0xF7}, true, []byte{0x2A}},
+		{"F0 47 ?? %% F7", []byte{0xF0, 0x48, 0x01, 0x2A, 0xF7}, false, nil},
+		{"F0 47 ?? %% F7", []byte{0xF0, 0x47, 0x01, 0x2A, 0x00, 0xF7}, false, nil},
+		{"F0 7D %* F7", []byte{0xF0, 0x7D, 0x01, 0x02, 0x03, 0xF7}, true, []byte{0x01, 0x02, 0x03}},
+		{"F0 7D %* F7", []byte{0xF0, 0x7D, 0xF7}, true, []byte{}},
+		{"F0 %% %* 01 F7", []byte{0xF0, 0x10, 0x20, 0x01, 0xF7}, true, []byte{0x10, 0x20}},
+	}
+
+	for _, c := range cases {
+		pattern, err := compileSysExPattern("test", c.pattern)
+		if err != nil {
+			t.Fatalf("unable to compile pattern {%s}: %v", c.pattern, err)
+		}
+		captured, ok := pattern.match(c.data)
+		if ok != c.matched || (ok && !bytes.Equal(captured, c.captured)) {
+			t.Fatalf("pattern {%s} on % X: expected (%v, % X), got (%v, % X)",
+				c.pattern, c.data, c.matched, c.captured, ok, captured)
+		}
+	}
+
+	if _, err := compileSysExPattern("test", "F0 ZZ F7"); err == nil {
+		t.Fatalf("expected error for invalid pattern token")
+	}
+}
+
+// Function checks that incoming SysEx is converted to signal of first matching pattern
+func TestSysExReceived(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.SysExSignals = []config.SysExSignal{
+		{Name: "fader", Pattern: "F0 7D 01 %% %% F7"},
+		{Name: "any", Pattern: "F0 %* F7"},
+	}
+	md, _, signals := newTestDevice(t, conf)
+
+	md.processMidiMessage(midi.SysEx([]byte{0x7D, 0x01, 0x12, 0x34}), 0)
+	md.processMidiMessage(midi.SysEx([]byte{0x7D, 0x02}), 0)
+
+	received := drainSignals(signals)
+	expectSignalCodes(t, received, "SysExReceived", "SysExReceived")
+	if sysEx := received[0].(model.SysExReceived); sysEx.Name != "fader" || sysEx.Captured != "12 34" {
+		t.Fatalf("unexpected sysex signal %+v", sysEx)
+	}
+	if sysEx := received[1].(model.SysExReceived); sysEx.Name != "any" || sysEx.Captured != "7D 02" {
+		t.Fatalf("unexpected sysex signal %+v", sysEx)
+	}
+}
@@ -0,0 +1,90 @@
+package midi
+
+import (
+	"math"
+	"midi_manipulator/pkg/model"
+	"time"
+
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+	"gitlab.com/gomidi/midi/v2"
+)
+
+const (
+	clocksPerQuarterNote   = 24
+	clocksPerSixteenthNote = 6
+	tempoWindowClocks      = 4 * clocksPerQuarterNote
+	clockTimeout           = time.Second
+)
+
+// Representation of transport state tracked by incoming MIDI clock
+type transportContext struct {
+	running     bool
+	position    int
+	clocks      int
+	ticks       []time.Time
+	tickCount   int
+	reportedBPM float64
+}
+
+// Function decodes MIDI realtime and song position messages into transport signals
+func (md *MidiDevice) decodeTransport(msg midi.Message) core.Signal {
+	var position uint16
+	switch {
+	case msg.Is(midi.TimingClockMsg):
+		// DRIVER TIMESTAMPS ARE SUMMED FROM ROUNDED MILLISECONDS, TEMPO IS MEASURED BY ARRIVAL TIME
+		return md.handleClock(md.clock.Now())
+	case msg.Is(midi.StartMsg):
+		md.transport.running = true
+		md.transport.position = 0
+		md.transport.clocks = 0
+		return model.TransportStarted{Device: md.name, Namespace: md.namespace}
+	case msg.Is(midi.ContinueMsg):
+		md.transport.running = true
+		return model.TransportStarted{Device: md.name, Namespace: md.namespace, Position: md.transport.position, Resumed: true}
+	case msg.Is(midi.StopMsg):
+		md.transport.running = false
+		return model.TransportStopped{Device: md.name, Namespace: md.namespace, Position: md.transport.position}
+	case msg.GetSPP(&position):
+		md.transport.position = int(position)
+		md.transport.clocks = 0
+	}
+	return nil
+}
+
+// Function advances song position by clock tick and returns tempo signal if smoothed tempo changed
+func (md *MidiDevice) handleClock(receivedAt time.Time) core.Signal {
+	tctx := &md.transport
+	if tctx.running {
+		tctx.clocks++
+		if tctx.clocks == clocksPerSixteenthNote {
+			tctx.clocks = 0
+			tctx.position++
+		}
+	}
+
+	if len(tctx.ticks) > 0 && receivedAt.Sub(tctx.ticks[len(tctx.ticks)-1]) > clockTimeout {
+		// CLOCK SOURCE WAS LOST, RESTART ESTIMATION
+		tctx.ticks = nil
+		tctx.tickCount = 0
+	}
+	tctx.ticks = append(tctx.ticks, receivedAt)
+	if len(tctx.ticks) > tempoWindowClocks+1 {
+		tctx.ticks = tctx.ticks[1:]
+	}
+	tctx.tickCount++
+	if tctx.tickCount%clocksPerQuarterNote != 0 {
+		return nil // tempo is estimated once per quarter note
+	}
+
+	elapsed := tctx.ticks[len(tctx.ticks)-1].Sub(tctx.ticks[0])
+	if elapsed <= 0 {
+		return nil
+	}
+	bpm := float64(len(tctx.ticks)-1) / clocksPerQuarterNote * float64(time.Minute) / float64(elapsed)
+	bpm = math.Round(bpm*10) / 10
+	if bpm == tctx.reportedBPM {
+		return nil
+	}
+	tctx.reportedBPM = bpm
+	return model.TempoChanged{Device: md.name, Namespace: md.namespace, BPM: bpm}
+}
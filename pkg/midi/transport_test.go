@@ -0,0 +1,94 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/model"
+	"testing"
+	"time"
+
+	"git.miem.hse.ru/hubman/hubman-lib/core"
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// Function sends clock ticks advancing fake clock by given interval between them
+func sendClocks(md *MidiDevice, fc *fakeClock, count int, interval time.Duration) {
+	for idx := 0; idx < count; idx++ {
+		md.processMidiMessage(midi.TimingClock(), 0)
+		fc.Advance(interval)
+	}
+}
+
+// Function returns tempo values of received TempoChanged signals
+func tempoValues(received []core.Signal) []float64 {
+	var values []float64
+	for _, signal := range received {
+		if tempo, ok := signal.(model.TempoChanged); ok {
+			values = append(values, tempo.BPM)
+		}
+	}
+	return values
+}
+
+// Function checks that tempo is estimated from clock and reported only when changed
+func TestTransportTempo(t *testing.T) {
+	md, fc, signals := newTestDevice(t, testDeviceConfig())
+
+	// 20ms PER CLOCK IS 125 BPM
+	sendClocks(md, fc, 4*clocksPerQuarterNote, 20*time.Millisecond)
+	if values := tempoValues(drainSignals(signals)); len(values) != 1 || values[0] != 125 {
+		t.Fatalf("expected single tempo 125, got %v", values)
+	}
+
+	// 25ms PER CLOCK IS 100 BPM, REACHED AFTER SMOOTHING WINDOW
+	sendClocks(md, fc, 8*clocksPerQuarterNote, 25*time.Millisecond)
+	values := tempoValues(drainSignals(signals))
+	if len(values) < 2 || values[len(values)-1] != 100 {
+		t.Fatalf("expected tempo smoothly changing to 100, got %v", values)
+	}
+	for idx := 1; idx < len(values); idx++ {
+		if values[idx] >= values[idx-1] {
+			t.Fatalf("expected decreasing tempo, got %v", values)
+		}
+	}
+}
+
+// Function checks that tempo with non-integer clock interval in milliseconds is measured exactly
+func TestTransportTempoFractionalInterval(t *testing.T) {
+	md, fc, signals := newTestDevice(t, testDeviceConfig())
+
+	// 120 BPM IS 20.833ms PER CLOCK
+	sendClocks(md, fc, 4*clocksPerQuarterNote, time.Minute/(120*clocksPerQuarterNote))
+	if values := tempoValues(drainSignals(signals)); len(values) != 1 || values[0] != 120 {
+		t.Fatalf("expected single tempo 120, got %v", values)
+	}
+}
+
+// Function checks transport signals and song position tracking
+func TestTransportStartStop(t *testing.T) {
+	md, fc, signals := newTestDevice(t, testDeviceConfig())
+
+	md.processMidiMessage(midi.Start(), 0)
+	sendClocks(md, fc, 3*clocksPerSixteenthNote, 20*time.Millisecond)
+	md.processMidiMessage(midi.Stop(), 0)
+	md.processMidiMessage(midi.SPP(32), 0)
+	md.processMidiMessage(midi.Continue(), 0)
+	sendClocks(md, fc, clocksPerSixteenthNote, 20*time.Millisecond)
+	md.processMidiMessage(midi.Stop(), 0)
+
+	received := drainSignals(signals)
+	expectSignalCodes(t, received, "TransportStarted", "TransportStopped", "TransportStarted", "TempoChanged", "TransportStopped")
+	if started := received[0].(model.TransportStarted); started.Position != 0 || started.Resumed {
+		t.Fatalf("unexpected start %+v", started)
+	}
+	if stopped := received[1].(model.TransportStopped); stopped.Position != 3 {
+		t.Fatalf("expected stop at position 3, got %+v", stopped)
+	}
+	if started := received[2].(model.TransportStarted); started.Position != 32 || !started.Resumed {
+		t.Fatalf("expected continue from position 32, got %+v", started)
+	}
+	if tempo := received[3].(model.TempoChanged); tempo.BPM != 125 {
+		t.Fatalf("expected tempo 125 after quarter note of clock, got %+v", tempo)
+	}
+	if stopped := received[4].(model.TransportStopped); stopped.Position != 33 {
+		t.Fatalf("expected stop at position 33, got %+v", stopped)
+	}
+}
@@ -0,0 +1,67 @@
+package midi
+
+import (
+	"math"
+	"midi_manipulator/pkg/config"
+)
+
+// Representation of velocity curve entity with precalculated lookup table
+type velocityCurve struct {
+	allKeys  bool
+	keyRange [2]uint8
+	table    [128]uint8
+}
+
+// Function calculates output velocity of curve for input velocity in range [1, 127]
+func curveVelocity(curve config.VelocityCurve, velocity int) int {
+	switch curve.Type {
+	case config.VelocityLogarithmic:
+		return int(math.Round(127 * math.Log1p(float64(velocity)) / math.Log(128)))
+	case config.VelocityExponential:
+		return int(math.Round(127 * math.Pow(float64(velocity)/127, 2)))
+	case config.VelocityFixed:
+		return curve.Value
+	case config.VelocityCustom:
+		// LINEAR INTERPOLATION BETWEEN EVENLY DISTRIBUTED POINTS OF TABLE
+		position := float64(velocity) * float64(len(curve.Table)-1) / 127
+		left := int(position)
+		if left >= len(curve.Table)-1 {
+			return curve.Table[len(curve.Table)-1]
+		}
+		weight := position - float64(left)
+		return int(math.Round(float64(curve.Table[left])*(1-weight) + float64(curve.Table[left+1])*weight))
+	default:
+		return velocity
+	}
+}
+
+// Function initializes velocity curve entity from configuration
+func newVelocityCurve(curve config.VelocityCurve) velocityCurve {
+	vc := velocityCurve{allKeys: len(curve.KeyRange) == 0}
+	if !vc.allKeys {
+		vc.keyRange = [2]uint8{uint8(curve.KeyRange[0]), uint8(curve.KeyRange[1])}
+	}
+	// ZERO VELOCITY IS KEPT AS IS
+	for velocity := 1; velocity < len(vc.table); velocity++ {
+		vc.table[velocity] = uint8(max(1, min(curveVelocity(curve, velocity), 127)))
+	}
+	return vc
+}
+
+// Function applies configuration of velocity curves to MIDI-device entity
+func (md *MidiDevice) applyVelocityCurves(curvesList []config.VelocityCurve) {
+	md.velocityCurves = make([]velocityCurve, 0, len(curvesList))
+	for _, curve := range curvesList {
+		md.velocityCurves = append(md.velocityCurves, newVelocityCurve(curve))
+	}
+}
+
+// Function maps velocity of key with first matching velocity curve
+func (md *MidiDevice) mapVelocity(key uint8, velocity uint8) uint8 {
+	for _, curve := range md.velocityCurves {
+		if curve.allKeys || (curve.keyRange[0] <= key && key <= curve.keyRange[1]) {
+			return curve.table[velocity&0x7F]
+		}
+	}
+	return velocity
+}
@@ -0,0 +1,56 @@
+package midi
+
+import (
+	"midi_manipulator/pkg/config"
+	"midi_manipulator/pkg/model"
+	"testing"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// Function checks output of every type of velocity curve
+func TestVelocityCurves(t *testing.T) {
+	cases := []struct {
+		curve    config.VelocityCurve
+		velocity uint8
+		expected uint8
+	}{
+		{config.VelocityCurve{Type: config.VelocityLinear}, 64, 64},
+		{config.VelocityCurve{Type: config.VelocityLogarithmic}, 1, 18},
+		{config.VelocityCurve{Type: config.VelocityLogarithmic}, 127, 127},
+		{config.VelocityCurve{Type: config.VelocityExponential}, 1, 1},
+		{config.VelocityCurve{Type: config.VelocityExponential}, 64, 32},
+		{config.VelocityCurve{Type: config.VelocityFixed, Value: 100}, 5, 100},
+		{config.VelocityCurve{Type: config.VelocityFixed, Value: 100}, 0, 0},
+		{config.VelocityCurve{Type: config.VelocityCustom, Table: []int{0, 127}}, 50, 50},
+		{config.VelocityCurve{Type: config.VelocityCustom, Table: []int{127, 0}}, 127, 1},
+		{config.VelocityCurve{Type: config.VelocityCustom, Table: []int{0, 100, 120}}, 127, 120},
+	}
+	for _, c := range cases {
+		if velocity := newVelocityCurve(c.curve).table[c.velocity]; velocity != c.expected {
+			t.Fatalf("curve %+v, velocity %d: expected %d, got %d", c.curve, c.velocity, c.expected, velocity)
+		}
+	}
+}
+
+// Function checks that first velocity curve matching key is applied before NotePushed is emitted
+func TestVelocityCurveKeyRanges(t *testing.T) {
+	conf := testDeviceConfig()
+	conf.VelocityCurves = []config.VelocityCurve{
+		{KeyRange: []int{36, 51}, Type: config.VelocityFixed, Value: 90},
+		{Type: config.VelocityExponential},
+	}
+	md, _, signals := newTestDevice(t, conf)
+
+	md.processMidiMessage(midi.NoteOn(0, 40, 10), 0)
+	md.processMidiMessage(midi.NoteOn(0, 60, 64), 0)
+
+	received := drainSignals(signals)
+	expectSignalCodes(t, received, "NotePushed", "NotePushed")
+	if pushed := received[0].(model.NotePushed); pushed.Velocity != 90 {
+		t.Fatalf("expected fixed velocity 90, got %+v", pushed)
+	}
+	if pushed := received[1].(model.NotePushed); pushed.Velocity != 32 {
+		t.Fatalf("expected exponential velocity 32, got %+v", pushed)
+	}
+}
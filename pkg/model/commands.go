@@ -9,6 +9,7 @@ type MidiCommand interface {
 // Representation of command to turn on the backlight of single component
 type TurnLightOnCommand struct {
 	KeyCode     int    `hubman:"key_code"`
+	KeyName     string `hubman:"key_name"`
 	DeviceAlias string `hubman:"device_alias"`
 	ColorName   string `hubman:"color_name"`
 }
@@ -26,6 +27,7 @@ func (c TurnLightOnCommand) Description() string {
 // Representation of command to turn off the backlight of single component
 type TurnLightOffCommand struct {
 	KeyCode     int    `hubman:"key_code"`
+	KeyName     string `hubman:"key_name"`
 	DeviceAlias string `hubman:"device_alias"`
 	ColorName   string `hubman:"color_name"`
 }
@@ -43,6 +45,7 @@ func (c TurnLightOffCommand) Description() string {
 // Representation of command to call backlight blink of single component
 type SingleBlinkCommand struct {
 	KeyCode     int    `hubman:"key_code"`
+	KeyName     string `hubman:"key_name"`
 	DeviceAlias string `hubman:"device_alias"`
 	ColorName   string `hubman:"color_name"`
 }
@@ -60,6 +63,7 @@ func (c SingleBlinkCommand) Description() string {
 // Representation of command to call backlight reversed blink of single component
 type SingleReversedBlinkCommand struct {
 	KeyCode     int    `hubman:"key_code"`
+	KeyName     string `hubman:"key_name"`
 	DeviceAlias string `hubman:"device_alias"`
 	ColorName   string `hubman:"color_name"`
 }
@@ -77,6 +81,7 @@ func (c SingleReversedBlinkCommand) Description() string {
 // Representation of command to call continuous backlight blink of single component
 type ContinuousBlinkCommand struct {
 	KeyCode     int    `hubman:"key_code"`
+	KeyName     string `hubman:"key_name"`
 	DeviceAlias string `hubman:"device_alias"`
 	ColorName   string `hubman:"color_name"`
 }
@@ -110,6 +115,7 @@ func (s SetActiveNamespaceCommand) Description() string {
 // Representation of command to start backlight blinking of single component
 type StartBlinkingCommand struct {
 	KeyCode      int    `hubman:"key_code"`
+	KeyName      string `hubman:"key_name"`
 	DeviceAlias  string `hubman:"device_alias"`
 	OnColorName  string `hubman:"on_color_name"`
 	OffColorName string `hubman:"off_color_name"`
@@ -128,6 +134,7 @@ func (s StartBlinkingCommand) Description() string {
 // Representation of command to stop backlight blinking of single component
 type StopBlinkingCommand struct {
 	KeyCode     int    `hubman:"key_code"`
+	KeyName     string `hubman:"key_name"`
 	DeviceAlias string `hubman:"device_alias"`
 }
 
@@ -140,3 +147,38 @@ func (s StopBlinkingCommand) Code() string {
 func (s StopBlinkingCommand) Description() string {
 	return "Make the key stop blinking if it blinks"
 }
+
+// Representation of command to set accumulated value of single control
+type SetControlValueCommand struct {
+	KeyCode     int    `hubman:"key_code"`
+	KeyName     string `hubman:"key_name"`
+	DeviceAlias string `hubman:"device_alias"`
+	Value       int    `hubman:"value"`
+}
+
+// Function returns string representation of model
+func (s SetControlValueCommand) Code() string {
+	return "SetControlValueCommand"
+}
+
+// Function returns string description of model
+func (s SetControlValueCommand) Description() string {
+	return "Sets accumulated value of specified control, value is clamped to configured value range"
+}
+
+// Representation of command to query accumulated value of single control
+type GetControlValueCommand struct {
+	KeyCode     int    `hubman:"key_code"`
+	KeyName     string `hubman:"key_name"`
+	DeviceAlias string `hubman:"device_alias"`
+}
+
+// Function returns string representation of model
+func (s GetControlValueCommand) Code() string {
+	return "GetControlValueCommand"
+}
+
+// Function returns string description of model
+func (s GetControlValueCommand) Description() string {
+	return "Requests accumulated value of specified control, answer is delivered as ControlValueReported signal"
+}
@@ -1,11 +1,22 @@
 package model
 
+// Kinds of keys reported in signals of key lifecycle
+const (
+	KeyKindNote    = "note"
+	KeyKindControl = "control"
+)
+
 // Representation of component click start event (NoteOn)
 type NotePushed struct {
 	Device    string `hubman:"device"`
 	Namespace string `hubman:"namespace"`
+	Channel   int    `hubman:"channel"`
+	Kind      string `hubman:"kind"`
 	KeyCode   int    `hubman:"key_code"`
+	KeyName   string `hubman:"key_name"`
 	Velocity  int    `hubman:"velocity"`
+	Timestamp int64  `hubman:"timestamp"`
+	Sequence  int64  `hubman:"sequence"`
 }
 
 // Function returns string representation of model
@@ -22,8 +33,14 @@ func (s NotePushed) Description() string {
 type NoteHold struct {
 	Device    string `hubman:"device"`
 	Namespace string `hubman:"namespace"`
+	Channel   int    `hubman:"channel"`
+	Kind      string `hubman:"kind"`
 	KeyCode   int    `hubman:"key_code"`
+	KeyName   string `hubman:"key_name"`
 	Velocity  int    `hubman:"velocity"`
+	Level     string `hubman:"level"`
+	Timestamp int64  `hubman:"timestamp"`
+	Sequence  int64  `hubman:"sequence"`
 }
 
 // Function returns string representation of model
@@ -33,15 +50,44 @@ func (s NoteHold) Code() string {
 
 // Function returns string description of model
 func (s NoteHold) Description() string {
-	return "NoteHold - signal represents state of key with 'Note' type that is pressed for long"
+	return "NoteHold - signal represents state of key with 'Note' type that is pressed for long enough to reach hold level"
+}
+
+// Representation of auto-repeat event of held component
+type NoteRepeat struct {
+	Device      string `hubman:"device"`
+	Namespace   string `hubman:"namespace"`
+	Channel     int    `hubman:"channel"`
+	Kind        string `hubman:"kind"`
+	KeyCode     int    `hubman:"key_code"`
+	KeyName     string `hubman:"key_name"`
+	Velocity    int    `hubman:"velocity"`
+	RepeatCount int    `hubman:"repeat_count"`
+	Timestamp   int64  `hubman:"timestamp"`
+	Sequence    int64  `hubman:"sequence"`
+}
+
+// Function returns string representation of model
+func (s NoteRepeat) Code() string {
+	return "NoteRepeat"
+}
+
+// Function returns string description of model
+func (s NoteRepeat) Description() string {
+	return "NoteRepeat - signal represents periodic repeat of key with 'Note' type while it is held"
 }
 
 // Representation of component click end event (NoteOff)
 type NoteReleased struct {
 	Device    string `hubman:"device"`
 	Namespace string `hubman:"namespace"`
+	Channel   int    `hubman:"channel"`
+	Kind      string `hubman:"kind"`
 	KeyCode   int    `hubman:"key_code"`
+	KeyName   string `hubman:"key_name"`
 	Velocity  int    `hubman:"velocity"`
+	Timestamp int64  `hubman:"timestamp"`
+	Sequence  int64  `hubman:"sequence"`
 }
 
 // Function returns string representation of model
@@ -58,8 +104,14 @@ func (s NoteReleased) Description() string {
 type NoteReleasedAfterHold struct {
 	Device    string `hubman:"device"`
 	Namespace string `hubman:"namespace"`
+	Channel   int    `hubman:"channel"`
+	Kind      string `hubman:"kind"`
 	KeyCode   int    `hubman:"key_code"`
+	KeyName   string `hubman:"key_name"`
 	Velocity  int    `hubman:"velocity"`
+	Level     string `hubman:"level"`
+	Timestamp int64  `hubman:"timestamp"`
+	Sequence  int64  `hubman:"sequence"`
 }
 
 // Function returns string representation of model
@@ -69,7 +121,135 @@ func (s NoteReleasedAfterHold) Code() string {
 
 // Function returns string description of model
 func (s NoteReleasedAfterHold) Description() string {
-	return "NoteReleasedAfterHold - signal represents state of key with 'Note' type right off it was released on a device after hold"
+	return "NoteReleasedAfterHold - signal represents state of key with 'Note' type right off it was released on a device after reaching hold level"
+}
+
+// Representation of toggle key state change event
+type NoteToggled struct {
+	Device    string `hubman:"device"`
+	Namespace string `hubman:"namespace"`
+	Channel   int    `hubman:"channel"`
+	KeyCode   int    `hubman:"key_code"`
+	KeyName   string `hubman:"key_name"`
+	State     bool   `hubman:"state"`
+	Timestamp int64  `hubman:"timestamp"`
+	Sequence  int64  `hubman:"sequence"`
+}
+
+// Function returns string representation of model
+func (s NoteToggled) Code() string {
+	return "NoteToggled"
+}
+
+// Function returns string description of model
+func (s NoteToggled) Description() string {
+	return "NoteToggled - signal represents new on/off state of toggle key with 'Note' type after it was pressed on a device"
+}
+
+// Representation of selection change event in exclusive group of keys
+type GroupSelectionChanged struct {
+	Device    string `hubman:"device"`
+	Namespace string `hubman:"namespace"`
+	GroupName string `hubman:"group_name"`
+	KeyCode   int    `hubman:"key_code"`
+	KeyName   string `hubman:"key_name"`
+	Timestamp int64  `hubman:"timestamp"`
+	Sequence  int64  `hubman:"sequence"`
+}
+
+// Function returns string representation of model
+func (s GroupSelectionChanged) Code() string {
+	return "GroupSelectionChanged"
+}
+
+// Function returns string description of model
+func (s GroupSelectionChanged) Description() string {
+	return "GroupSelectionChanged - signal represents key newly selected by press in exclusive group of keys"
+}
+
+// Representation of component double tap event (two short clicks within tap window)
+type NoteDoubleTapped struct {
+	Device    string `hubman:"device"`
+	Namespace string `hubman:"namespace"`
+	Channel   int    `hubman:"channel"`
+	Kind      string `hubman:"kind"`
+	KeyCode   int    `hubman:"key_code"`
+	KeyName   string `hubman:"key_name"`
+	Velocity  int    `hubman:"velocity"`
+	Timestamp int64  `hubman:"timestamp"`
+	Sequence  int64  `hubman:"sequence"`
+}
+
+// Function returns string representation of model
+func (s NoteDoubleTapped) Code() string {
+	return "NoteDoubleTapped"
+}
+
+// Function returns string description of model
+func (s NoteDoubleTapped) Description() string {
+	return "NoteDoubleTapped - signal represents key with 'Note' type that was pressed and released twice within tap window"
+}
+
+// Representation of component multi tap event (three or more short clicks within tap window)
+type NoteMultiTapped struct {
+	Device    string `hubman:"device"`
+	Namespace string `hubman:"namespace"`
+	Channel   int    `hubman:"channel"`
+	Kind      string `hubman:"kind"`
+	KeyCode   int    `hubman:"key_code"`
+	KeyName   string `hubman:"key_name"`
+	Velocity  int    `hubman:"velocity"`
+	TapCount  int    `hubman:"tap_count"`
+	Timestamp int64  `hubman:"timestamp"`
+	Sequence  int64  `hubman:"sequence"`
+}
+
+// Function returns string representation of model
+func (s NoteMultiTapped) Code() string {
+	return "NoteMultiTapped"
+}
+
+// Function returns string description of model
+func (s NoteMultiTapped) Description() string {
+	return "NoteMultiTapped - signal represents key with 'Note' type that was pressed and released three or more times within tap window"
+}
+
+// Representation of key combination press event
+type ChordPressed struct {
+	Device    string `hubman:"device"`
+	Namespace string `hubman:"namespace"`
+	ChordName string `hubman:"chord_name"`
+	Timestamp int64  `hubman:"timestamp"`
+	Sequence  int64  `hubman:"sequence"`
+}
+
+// Function returns string representation of model
+func (s ChordPressed) Code() string {
+	return "ChordPressed"
+}
+
+// Function returns string description of model
+func (s ChordPressed) Description() string {
+	return "ChordPressed - signal represents configured combination of keys that were pressed together within chord window"
+}
+
+// Representation of key combination release event
+type ChordReleased struct {
+	Device    string `hubman:"device"`
+	Namespace string `hubman:"namespace"`
+	ChordName string `hubman:"chord_name"`
+	Timestamp int64  `hubman:"timestamp"`
+	Sequence  int64  `hubman:"sequence"`
+}
+
+// Function returns string representation of model
+func (s ChordReleased) Code() string {
+	return "ChordReleased"
+}
+
+// Function returns string description of model
+func (s ChordReleased) Description() string {
+	return "ChordReleased - signal represents release of any key from previously pressed combination of keys"
 }
 
 // ControlPushed В MIDI Control имеет только один тип событий "ControlChange",
@@ -79,8 +259,13 @@ func (s NoteReleasedAfterHold) Description() string {
 type ControlPushed struct {
 	Device    string `hubman:"device"`
 	Namespace string `hubman:"namespace"`
+	Channel   int    `hubman:"channel"`
 	KeyCode   int    `hubman:"key_code"`
+	KeyName   string `hubman:"key_name"`
 	Value     int    `hubman:"velocity"`
+	Delta     int    `hubman:"delta"`
+	Timestamp int64  `hubman:"timestamp"`
+	Sequence  int64  `hubman:"sequence"`
 }
 
 // Function returns string representation of model
@@ -93,12 +278,216 @@ func (s ControlPushed) Description() string {
 	return "ControlPushed - signal represents state of key with 'Control' type right off it was pressed on a device"
 }
 
+// Representation of current accumulated value of control
+type ControlValueReported struct {
+	Device    string `hubman:"device"`
+	Namespace string `hubman:"namespace"`
+	KeyCode   int    `hubman:"key_code"`
+	KeyName   string `hubman:"key_name"`
+	Value     int    `hubman:"value"`
+	Timestamp int64  `hubman:"timestamp"`
+	Sequence  int64  `hubman:"sequence"`
+}
+
+// Function returns string representation of model
+func (s ControlValueReported) Code() string {
+	return "ControlValueReported"
+}
+
+// Function returns string description of model
+func (s ControlValueReported) Description() string {
+	return "ControlValueReported - signal represents current accumulated value of control, e.g. restored from bank of namespace"
+}
+
+// Representation of high resolution control change event (14-bit ControlChange, NRPN or RPN)
+type HighResolutionControlPushed struct {
+	Device    string `hubman:"device"`
+	Namespace string `hubman:"namespace"`
+	Channel   int    `hubman:"channel"`
+	Type      string `hubman:"type"`
+	KeyCode   int    `hubman:"key_code"`
+	Value     int    `hubman:"value"`
+	Timestamp int64  `hubman:"timestamp"`
+	Sequence  int64  `hubman:"sequence"`
+}
+
+// Function returns string representation of model
+func (s HighResolutionControlPushed) Code() string {
+	return "HighResolutionControlPushed"
+}
+
+// Function returns string description of model
+func (s HighResolutionControlPushed) Description() string {
+	return "HighResolutionControlPushed - signal represents 14-bit value in range [0, 16383] of control reassembled from MSB/LSB pair or NRPN/RPN parameter"
+}
+
+// Representation of pitch bend wheel change event (PitchBend)
+type PitchBendChanged struct {
+	Device    string `hubman:"device"`
+	Namespace string `hubman:"namespace"`
+	Channel   int    `hubman:"channel"`
+	Value     int    `hubman:"value"`
+	Timestamp int64  `hubman:"timestamp"`
+	Sequence  int64  `hubman:"sequence"`
+}
+
+// Function returns string representation of model
+func (s PitchBendChanged) Code() string {
+	return "PitchBendChanged"
+}
+
+// Function returns string description of model
+func (s PitchBendChanged) Description() string {
+	return "PitchBendChanged - signal represents position of pitch bend wheel as signed 14-bit value in range [-8192, 8191]"
+}
+
+// Representation of channel aftertouch change event (ChannelPressure)
+type ChannelPressureChanged struct {
+	Device    string `hubman:"device"`
+	Namespace string `hubman:"namespace"`
+	Channel   int    `hubman:"channel"`
+	Pressure  int    `hubman:"pressure"`
+	Timestamp int64  `hubman:"timestamp"`
+	Sequence  int64  `hubman:"sequence"`
+}
+
+// Function returns string representation of model
+func (s ChannelPressureChanged) Code() string {
+	return "ChannelPressureChanged"
+}
+
+// Function returns string description of model
+func (s ChannelPressureChanged) Description() string {
+	return "ChannelPressureChanged - signal represents pressure applied to keys of whole channel"
+}
+
+// Representation of polyphonic aftertouch change event (PolyAftertouch)
+type PolyAftertouchChanged struct {
+	Device    string `hubman:"device"`
+	Namespace string `hubman:"namespace"`
+	Channel   int    `hubman:"channel"`
+	KeyCode   int    `hubman:"key_code"`
+	KeyName   string `hubman:"key_name"`
+	Pressure  int    `hubman:"pressure"`
+	Timestamp int64  `hubman:"timestamp"`
+	Sequence  int64  `hubman:"sequence"`
+}
+
+// Function returns string representation of model
+func (s PolyAftertouchChanged) Code() string {
+	return "PolyAftertouchChanged"
+}
+
+// Function returns string description of model
+func (s PolyAftertouchChanged) Description() string {
+	return "PolyAftertouchChanged - signal represents pressure applied to single key with 'Note' type"
+}
+
+// Representation of program change event (ProgramChange)
+type ProgramChanged struct {
+	Device    string `hubman:"device"`
+	Namespace string `hubman:"namespace"`
+	Channel   int    `hubman:"channel"`
+	Program   int    `hubman:"program"`
+	Timestamp int64  `hubman:"timestamp"`
+	Sequence  int64  `hubman:"sequence"`
+}
+
+// Function returns string representation of model
+func (s ProgramChanged) Code() string {
+	return "ProgramChanged"
+}
+
+// Function returns string description of model
+func (s ProgramChanged) Description() string {
+	return "ProgramChanged - signal represents program (patch) selected on a device"
+}
+
+// Representation of SysEx message matching configured pattern
+type SysExReceived struct {
+	Device    string `hubman:"device"`
+	Namespace string `hubman:"namespace"`
+	Name      string `hubman:"pattern_name"`
+	Captured  string `hubman:"captured"`
+	Timestamp int64  `hubman:"timestamp"`
+	Sequence  int64  `hubman:"sequence"`
+}
+
+// Function returns string representation of model
+func (s SysExReceived) Code() string {
+	return "SysExReceived"
+}
+
+// Function returns string description of model
+func (s SysExReceived) Description() string {
+	return "SysExReceived - signal represents SysEx message matching configured pattern, captured bytes are listed in hex separated by spaces"
+}
+
+// Representation of transport start event (Start or Continue)
+type TransportStarted struct {
+	Device    string `hubman:"device"`
+	Namespace string `hubman:"namespace"`
+	Position  int    `hubman:"position"`
+	Resumed   bool   `hubman:"resumed"`
+	Timestamp int64  `hubman:"timestamp"`
+	Sequence  int64  `hubman:"sequence"`
+}
+
+// Function returns string representation of model
+func (s TransportStarted) Code() string {
+	return "TransportStarted"
+}
+
+// Function returns string description of model
+func (s TransportStarted) Description() string {
+	return "TransportStarted - signal represents start or continuation of playback by MIDI clock source from position in sixteenth notes"
+}
+
+// Representation of transport stop event
+type TransportStopped struct {
+	Device    string `hubman:"device"`
+	Namespace string `hubman:"namespace"`
+	Position  int    `hubman:"position"`
+	Timestamp int64  `hubman:"timestamp"`
+	Sequence  int64  `hubman:"sequence"`
+}
+
+// Function returns string representation of model
+func (s TransportStopped) Code() string {
+	return "TransportStopped"
+}
+
+// Function returns string description of model
+func (s TransportStopped) Description() string {
+	return "TransportStopped - signal represents stop of playback by MIDI clock source at position in sixteenth notes"
+}
+
+// Representation of tempo change event
+type TempoChanged struct {
+	Device    string  `hubman:"device"`
+	Namespace string  `hubman:"namespace"`
+	BPM       float64 `hubman:"bpm"`
+	Timestamp int64   `hubman:"timestamp"`
+	Sequence  int64   `hubman:"sequence"`
+}
+
+// Function returns string representation of model
+func (s TempoChanged) Code() string {
+	return "TempoChanged"
+}
+
+// Function returns string description of model
+func (s TempoChanged) Description() string {
+	return "TempoChanged - signal represents change of tempo estimated from incoming MIDI clock"
+}
 
 // Representation of namespace change event
 type NamespaceChanged struct {
 	Device       string `hubman:"device"`
 	OldNamespace string `hubman:"old_namespace"`
 	NewNamespace string `hubman:"new_namespace"`
+	Timestamp    int64  `hubman:"timestamp"`
+	Sequence     int64  `hubman:"sequence"`
 }
 
 // Function returns string representation of model
@@ -0,0 +1,104 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Delay between first unsaved change and write of state file
+const fileStoreFlushDelay = time.Second
+
+// Representation of state store entity persisting values to local JSON file
+type FileStore struct {
+	path   string
+	mutex  sync.Mutex
+	values map[string]map[string]map[string]int
+	flush  *time.Timer
+	logger *zap.Logger
+}
+
+// Function initializes file store entity with values read from file, missing file is treated as empty store
+func NewFileStore(path string, logger *zap.Logger) (*FileStore, error) {
+	fs := FileStore{path: path, values: make(map[string]map[string]map[string]int), logger: logger}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &fs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fs.values); err != nil {
+		return nil, err
+	}
+	return &fs, nil
+}
+
+// Function returns saved values of controls by key for given device and namespace
+func (fs *FileStore) Load(device string, namespace string) map[int]int {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	values := make(map[int]int)
+	for key, value := range fs.values[device][namespace] {
+		keyCode, err := strconv.Atoi(key)
+		if err != nil {
+			continue
+		}
+		values[keyCode] = value
+	}
+	return values
+}
+
+// Function saves value of single control of given device and namespace and schedules write of file
+func (fs *FileStore) Save(device string, namespace string, key int, value int) {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	if _, ok := fs.values[device]; !ok {
+		fs.values[device] = make(map[string]map[string]int)
+	}
+	if _, ok := fs.values[device][namespace]; !ok {
+		fs.values[device][namespace] = make(map[string]int)
+	}
+	fs.values[device][namespace][strconv.Itoa(key)] = value
+
+	if fs.flush == nil {
+		fs.flush = time.AfterFunc(fileStoreFlushDelay, func() {
+			if err := fs.Flush(); err != nil {
+				fs.logger.Warn("Unable to save control state", zap.String("path", fs.path), zap.Error(err))
+			}
+		})
+	}
+}
+
+// Function writes current values to file
+func (fs *FileStore) Flush() error {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	if fs.flush != nil {
+		fs.flush.Stop()
+		fs.flush = nil
+	}
+
+	data, err := json.MarshalIndent(fs.values, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(fs.path), 0o755); err != nil {
+		return err
+	}
+	tmpPath := fs.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, fs.path)
+}
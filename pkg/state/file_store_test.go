@@ -0,0 +1,40 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// Function checks that saved values survive reopening of file store
+func TestFileStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "controls.json")
+
+	fs, err := NewFileStore(path, zap.NewNop())
+	if err != nil {
+		t.Fatalf("unable to open missing state file: %v", err)
+	}
+	fs.Save("MPD226", "default", 16, 42)
+	fs.Save("MPD226", "default", 17, 7)
+	fs.Save("MPD226", "mixer", 16, 1)
+	fs.Save("Arduino", "default", 16, 100)
+	if err := fs.Flush(); err != nil {
+		t.Fatalf("unable to flush state file: %v", err)
+	}
+
+	reopened, err := NewFileStore(path, zap.NewNop())
+	if err != nil {
+		t.Fatalf("unable to reopen state file: %v", err)
+	}
+	values := reopened.Load("MPD226", "default")
+	if len(values) != 2 || values[16] != 42 || values[17] != 7 {
+		t.Fatalf("unexpected restored values %v", values)
+	}
+	if values := reopened.Load("MPD226", "mixer"); len(values) != 1 || values[16] != 1 {
+		t.Fatalf("unexpected restored values of namespace %v", values)
+	}
+	if values := reopened.Load("unknown", "default"); len(values) != 0 {
+		t.Fatalf("expected no values for unknown device, got %v", values)
+	}
+}
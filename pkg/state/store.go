@@ -0,0 +1,9 @@
+package state
+
+// Representation of storage for accumulated values of device controls
+type Store interface {
+	// Function returns saved values of controls by key for given device and namespace
+	Load(device string, namespace string) map[int]int
+	// Function saves value of single control of given device and namespace
+	Save(device string, namespace string, key int, value int)
+}